@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mcp/internal/reqctx"
+	"mcp/internal/resources"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// CodeInsufficientScope 是调用方缺少工具或资源所需权限范围时返回的 JSON-RPC 错误码
+const CodeInsufficientScope = -32003
+
+func insufficientScope(t RegisteredTool) error {
+	return &jsonrpc.Error{
+		Code:    CodeInsufficientScope,
+		Message: fmt.Sprintf("insufficient scope: tool %s requires %s", t.Tool.Name, strings.Join(t.Scopes, ", ")),
+	}
+}
+
+func insufficientResourceScope(uri string, scopes []string) error {
+	return &jsonrpc.Error{
+		Code:    CodeInsufficientScope,
+		Message: fmt.Sprintf("insufficient scope: resource %s requires %s", uri, strings.Join(scopes, ", ")),
+	}
+}
+
+// resourceAllowed 判断调用方是否可以访问该类资源，所需的权限范围取自对应工具在 tools.scopes 中的配置，
+// 因此能调用 diarySearch 的调用方也能读取 diary:// 资源；未开启认证时不做限制
+func (s *MCPServer) resourceAllowed(r resources.Resource, p *reqctx.Principal) bool {
+	return RegisteredTool{Scopes: s.Tools.Scopes(r.Tool())}.Allowed(p)
+}
+
+// toolScopeMiddleware 按当前请求调用方的权限范围过滤 tools/list 并拦截 tools/call
+// 作用于 SDK server，因此 Streamable HTTP 与 SSE 两种传输的行为一致
+func toolScopeMiddleware(tools *ToolRegistry) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			principal := reqctx.From(ctx).Principal
+			if principal == nil {
+				return next(ctx, method, req)
+			}
+
+			switch method {
+			case "tools/call":
+				params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+				if !ok {
+					break
+				}
+				if entry, ok := tools.Get(params.Name); ok && !entry.Allowed(principal) {
+					return nil, insufficientScope(entry)
+				}
+			case "tools/list":
+				res, err := next(ctx, method, req)
+				if list, ok := res.(*mcp.ListToolsResult); ok && err == nil {
+					allowed := list.Tools[:0]
+					for _, t := range list.Tools {
+						if entry, ok := tools.Get(t.Name); !ok || entry.Allowed(principal) {
+							allowed = append(allowed, t)
+						}
+					}
+					list.Tools = allowed
+				}
+				return res, err
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// resourceScopeMiddleware 拦截调用方缺少权限范围的 resources/read 与 resources/subscribe
+// resources/list 中的动态资源由 listResourcesMiddleware 按权限范围过滤
+func resourceScopeMiddleware(s *MCPServer) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			principal := reqctx.From(ctx).Principal
+			if principal == nil {
+				return next(ctx, method, req)
+			}
+
+			var uri string
+			switch params := req.GetParams().(type) {
+			case *mcp.ReadResourceParams:
+				uri = params.URI
+			case *mcp.SubscribeParams:
+				uri = params.URI
+			default:
+				return next(ctx, method, req)
+			}
+			if r, ok := s.findResource(uri); ok && !s.resourceAllowed(r, principal) {
+				return nil, insufficientResourceScope(uri, s.Tools.Scopes(r.Tool()))
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
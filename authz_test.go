@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mcp/config"
+	"mcp/internal/reqctx"
+	"mcp/internal/resources"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newScopeTestServer 创建只包含资源与工具注册表的 MCPServer，不连接后端
+func newScopeTestServer(cfg config.ToolsConfig) *MCPServer {
+	s := &MCPServer{Server: mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)}
+	s.Tools = NewToolRegistry(s.Server, cfg)
+	s.resources = []resources.Resource{resources.NewDiaryResource(), resources.NewMemoryResource()}
+	return s
+}
+
+func TestResourceScopeFollowsToolScopes(t *testing.T) {
+	scoped := config.ToolsConfig{Scopes: []config.ToolScopeConfig{
+		{Tool: "diarySearch", Scopes: []string{"diary:read"}},
+		{Tool: "memorySearch", Scopes: []string{"memory:read"}},
+	}}
+	alice := &reqctx.Principal{UserID: "alice", Scopes: []string{"diary:read"}}
+	bob := &reqctx.Principal{UserID: "bob"}
+
+	tests := []struct {
+		name      string
+		cfg       config.ToolsConfig
+		principal *reqctx.Principal
+		uri       string
+		allowed   bool
+	}{
+		{"default config allows any caller", config.ToolsConfig{}, bob, "diary://1", true},
+		{"no authentication", scoped, nil, "diary://1", true},
+		{"caller has the tool scope", scoped, alice, "diary://1", true},
+		{"caller lacks the tool scope", scoped, alice, "memory://1", false},
+		{"caller without scopes", scoped, bob, "diary://1", false},
+		{"unknown scheme is left to the SDK", scoped, bob, "other://1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScopeTestServer(tt.cfg)
+			next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				return &mcp.ReadResourceResult{}, nil
+			}
+			ctx := reqctx.With(context.Background(), &reqctx.Info{Principal: tt.principal})
+			req := &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: tt.uri}}
+
+			_, err := resourceScopeMiddleware(s)(next)(ctx, "resources/read", req)
+			if tt.allowed && err != nil {
+				t.Fatalf("resources/read: %v", err)
+			}
+			var wireErr *jsonrpc.Error
+			if !tt.allowed && (!errors.As(err, &wireErr) || wireErr.Code != CodeInsufficientScope) {
+				t.Fatalf("resources/read err = %v, want code %d", err, CodeInsufficientScope)
+			}
+		})
+	}
+}
+
+func TestListResourcesSkipsResourcesWithoutScope(t *testing.T) {
+	s := newScopeTestServer(config.ToolsConfig{Scopes: []config.ToolScopeConfig{
+		{Tool: "diarySearch", Scopes: []string{"diary:read"}},
+		{Tool: "memorySearch", Scopes: []string{"memory:read"}},
+	}})
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.ListResourcesResult{}, nil
+	}
+	// 调用方没有任何权限范围时不会向后端列出资源
+	ctx := reqctx.With(context.Background(), &reqctx.Info{Principal: &reqctx.Principal{UserID: "bob"}})
+	res, err := listResourcesMiddleware(s, 10)(next)(ctx, "resources/list", &mcp.ListResourcesRequest{Params: &mcp.ListResourcesParams{}})
+	if err != nil {
+		t.Fatalf("resources/list: %v", err)
+	}
+	if list := res.(*mcp.ListResourcesResult); len(list.Resources) != 0 {
+		t.Fatalf("resources = %v, want none", list.Resources)
+	}
+}
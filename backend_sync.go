@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"mcp/internal/grpc"
+	ext_tools "mcp/internal/tools"
+	"mcp/pkg/log"
+	pb "mcp/proto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// backendToolSync 记录由后端 ListTools 同步进注册表的工具，以便后续增量更新
+type backendToolSync struct {
+	mu    sync.Mutex
+	tools map[string]*pb.ToolDefinition
+}
+
+// SyncBackendTools 从后端拉取一次工具列表并同步到注册表：
+// 新增或定义变化的工具会被注册，后端不再声明的工具会被移除，与本地工具重名的后端工具会被跳过
+func (s *MCPServer) SyncBackendTools(ctx context.Context) error {
+	res, err := grpc.ListTools(ctx, &pb.ListToolsRequest{})
+	if err != nil {
+		return err
+	}
+
+	s.backendSync.mu.Lock()
+	defer s.backendSync.mu.Unlock()
+
+	if s.backendSync.tools == nil {
+		s.backendSync.tools = make(map[string]*pb.ToolDefinition)
+	}
+	synced := s.backendSync.tools
+
+	seen := make(map[string]bool, len(res.Tools))
+	for _, def := range res.Tools {
+		seen[def.Name] = true
+		if old, ok := synced[def.Name]; ok && proto.Equal(old, def) {
+			continue
+		}
+		if _, ok := synced[def.Name]; !ok {
+			if _, exists := s.Tools.Get(def.Name); exists {
+				log.Warn("后端工具与本地工具重名，已跳过", "tool", def.Name)
+				continue
+			}
+		}
+
+		backendTool, err := ext_tools.NewBackendTool(def)
+		if err != nil {
+			log.Warn("无法注册后端工具", "tool", def.Name, "error", err)
+			continue
+		}
+		s.Tools.RegisterRaw(backendTool.GetToolDef(), backendTool.Handle)
+		synced[def.Name] = def
+		log.Info("已同步后端工具", "tool", def.Name)
+	}
+
+	for name := range synced {
+		if !seen[name] {
+			s.Tools.Remove(name)
+			delete(synced, name)
+			log.Info("已移除后端工具", "tool", name)
+		}
+	}
+	return nil
+}
+
+// RunBackendToolSync 按固定间隔同步后端工具，直到 ctx 被取消
+func (s *MCPServer) RunBackendToolSync(ctx context.Context, interval time.Duration) {
+	runSync := func() {
+		syncCtx, cancel := context.WithTimeout(ctx, interval)
+		defer cancel()
+		if err := s.SyncBackendTools(syncCtx); err != nil {
+			log.Warn("同步后端工具失败", "error", err)
+		}
+	}
+
+	runSync()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			runSync()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
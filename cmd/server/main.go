@@ -1,11 +1,23 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"mcp/pkg/log"
 
 	mcp_impl "mcp"
 	"mcp/config"
+	"mcp/internal/auth"
+	"mcp/internal/certs"
+	"mcp/internal/grpc"
 	"mcp/internal/router"
 )
 
@@ -18,13 +30,104 @@ func main() {
 	// 初始化日志系统
 	log.Init(cfg)
 
-	srv := mcp_impl.NewMCPServer(cfg)
+	// 收到 SIGINT/SIGTERM 后取消 ctx，后台任务随之退出
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := mcp_impl.NewMCPServer(ctx, cfg)
+
+	// 构建 API Key / OAuth 令牌校验，均未开启时仅透传 API Key
+	var keyStore auth.KeyStore
+	if cfg.Auth.Enabled || cfg.Auth.OAuth.Enabled {
+		keyStore, err = auth.NewKeyStore(ctx, cfg.Auth)
+		if err != nil {
+			log.Fatal("无法初始化 API Key 存储", "error", err)
+		}
+	}
 
-	r := router.Setup(cfg, srv)
+	r := router.Setup(cfg, srv, keyStore)
 
 	port := fmt.Sprintf("%d", cfg.Server.Port)
+	httpSrv := &http.Server{
+		Addr:    ":" + port,
+		Handler: r,
+	}
+
+	tlsCfg := cfg.Server.TLS
+	if tlsCfg.Enabled {
+		// HTTPS：证书文件变化后在下一次握手时生效，无需重启
+		reloader, err := certs.NewReloader(tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.ClientCAFile)
+		if err != nil {
+			log.Fatal("无法加载 TLS 证书", "error", err)
+		}
+		clientAuth := tls.NoClientCert
+		switch tlsCfg.ClientAuth {
+		case "verify_if_given":
+			clientAuth = tls.VerifyClientCertIfGiven
+		case "require":
+			clientAuth = tls.RequireAndVerifyClientCert
+		}
+		httpSrv.TLSConfig = reloader.ServerConfig(clientAuth)
+		log.Info("已开启 TLS", "client_auth", tlsCfg.ClientAuth)
+	}
+
 	log.Info(fmt.Sprintf("正在启动 MCP 服务，基于端口 :%s", port))
 	log.Info("  - Streamable HTTP 协议: POST /mcp (推荐)")
 	log.Info("  - 传统 SSE 协议: GET /sse + POST /messages")
-	r.Run(":" + port)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if tlsCfg.Enabled {
+			serveErr <- httpSrv.ListenAndServeTLS("", "")
+		} else {
+			serveErr <- httpSrv.ListenAndServe()
+		}
+	}()
+
+	// 开启 TLS 时容器健康检查无法直接探测 HTTPS 端口，可另行以明文 HTTP 提供 /health
+	var healthSrv *http.Server
+	if cfg.Server.HealthPort > 0 {
+		healthSrv = &http.Server{
+			Addr:    fmt.Sprintf(":%d", cfg.Server.HealthPort),
+			Handler: router.SetupHealth(cfg),
+		}
+		log.Info(fmt.Sprintf("  - 健康检查: GET /health (HTTP :%d)", cfg.Server.HealthPort))
+		go func() {
+			serveErr <- healthSrv.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		log.Fatal("HTTP 服务异常退出", "error", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	shutdown(srv, httpSrv, healthSrv, cfg.Server.ShutdownTimeout)
+}
+
+// shutdown 优雅退出：拒绝新的会话并等待进行中的请求完成，随后关闭全部会话、HTTP 服务与后端连接
+// 健康检查端口在最后关闭，排空期间仍可被探测；healthSrv 为 nil 表示未开启
+func shutdown(srv *mcp_impl.MCPServer, httpSrv, healthSrv *http.Server, timeout time.Duration) {
+	log.Info("收到退出信号，开始排空连接", "timeout", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Drain(ctx); err != nil {
+		log.Warn("等待进行中的请求超时", "error", err)
+	}
+	log.Info("已关闭 MCP 会话", "count", srv.CloseSessions())
+
+	if err := httpSrv.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Warn("HTTP 服务未能在超时前关闭，强制断开剩余连接", "error", err)
+		httpSrv.Close()
+	}
+	if healthSrv != nil {
+		healthSrv.Close()
+	}
+	if err := grpc.Close(); err != nil {
+		log.Warn("关闭 gRPC 连接失败", "error", err)
+	}
+	log.Info("MCP 服务已退出")
 }
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// CircuitBreakerConfig 熔断器配置
+// 连续失败（包括耗时超过 SlowCallThreshold 的调用）达到阈值后熔断，熔断期间调用立即失败；
+// 经过 OpenTimeout 后放行少量探测调用，探测成功则恢复，失败则继续熔断
+type CircuitBreakerConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ConsecutiveFailures 触发熔断的连续失败次数
+	ConsecutiveFailures int `mapstructure:"consecutive_failures"`
+	// SlowCallThreshold 耗时超过该值的调用即使成功也记为失败，0 表示不按耗时判断
+	SlowCallThreshold time.Duration `mapstructure:"slow_call_threshold"`
+	// OpenTimeout 熔断后等待多久开始探测恢复
+	OpenTimeout time.Duration `mapstructure:"open_timeout"`
+	// HalfOpenMaxCalls 探测阶段同时放行的调用数
+	HalfOpenMaxCalls int `mapstructure:"half_open_max_calls"`
+}
+
+func validateBreaker(name string, b CircuitBreakerConfig) error {
+	if !b.Enabled {
+		return nil
+	}
+	if b.ConsecutiveFailures < 1 {
+		return fmt.Errorf("%s.consecutive_failures 必须大于 0", name)
+	}
+	if b.OpenTimeout <= 0 {
+		return fmt.Errorf("%s.open_timeout 必须大于 0", name)
+	}
+	if b.HalfOpenMaxCalls < 1 {
+		return fmt.Errorf("%s.half_open_max_calls 必须大于 0", name)
+	}
+	return nil
+}
@@ -1,28 +1,246 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type MCPConfig struct {
-	Server ServerConfig `mapstructure:"server"`
-	Search SearchConfig `mapstructure:"search"`
-	Grpc   GrpcConfig   `mapstructure:"grpc"`
-	Log    LogConfig    `mapstructure:"log"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Streamable StreamableConfig `mapstructure:"streamable"`
+	CORS       CORSConfig       `mapstructure:"cors"`
+	Search     SearchConfig     `mapstructure:"search"`
+	Tools      ToolsConfig      `mapstructure:"tools"`
+	Resources  ResourcesConfig  `mapstructure:"resources"`
+	Prompts    PromptsConfig    `mapstructure:"prompts"`
+	Auth       AuthConfig       `mapstructure:"auth"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	Admin      AdminConfig      `mapstructure:"admin"`
+	Grpc       GrpcConfig       `mapstructure:"grpc"`
+	Log        LogConfig        `mapstructure:"log"`
 }
 
 type ServerConfig struct {
 	Port int    `mapstructure:"port"`
 	Env  string `mapstructure:"env"`
+	// TLS 为 HTTP 监听开启 TLS/mTLS
+	TLS ServerTLSConfig `mapstructure:"tls"`
+	// ShutdownTimeout 收到退出信号后等待进行中请求完成与连接关闭的最长时间
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// RequestTimeout 单次工具调用、资源读取与提示词获取的最长时间，0 表示不限制
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	// HealthPort 额外以明文 HTTP 提供 /health 的端口，供开启 TLS 时的容器健康检查使用，0 表示不开启
+	HealthPort int `mapstructure:"health_port"`
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，只有来自这些地址的请求才按 X-Forwarded-For 识别客户端 IP；
+	// 为空时不信任任何代理，客户端 IP 取自连接的对端地址
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// ServerTLSConfig HTTPS 监听配置，证书文件变化后自动重新加载
+type ServerTLSConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// ClientAuth 客户端证书校验方式：none（默认）、verify_if_given、require
+	ClientAuth string `mapstructure:"client_auth"`
+	// ClientCAFile 校验客户端证书的 CA，ClientAuth 不为 none 时必填
+	ClientCAFile string `mapstructure:"client_ca_file"`
+	// ClientPrincipals 按证书主题的 CN 映射调用方身份；未列出的证书以 CN 作为用户 ID，不授予权限范围
+	ClientPrincipals []ClientCertPrincipalConfig `mapstructure:"client_principals"`
+}
+
+// ClientCertPrincipalConfig 将客户端证书映射为调用方身份
+type ClientCertPrincipalConfig struct {
+	CommonName string `mapstructure:"common_name"`
+	// UserID 默认为 CommonName
+	UserID string   `mapstructure:"user_id"`
+	Scopes []string `mapstructure:"scopes"`
+}
+
+// StreamableConfig 配置 Streamable HTTP 传输 (/mcp)
+type StreamableConfig struct {
+	// JSONResponse 为 true 时，请求以 application/json 响应，否则以 text/event-stream 响应
+	JSONResponse bool `mapstructure:"json_response"`
+	// Stateless 为 true 时不签发也不校验 Mcp-Session-Id
+	Stateless bool `mapstructure:"stateless"`
+	// SessionTimeout 空闲 session 的超时时间，0 表示永不过期
+	SessionTimeout time.Duration `mapstructure:"session_timeout"`
 }
 
 type SearchConfig struct {
+	// Provider、APIKey 与 CX 配置单个搜索提供者，配置了 Providers 时忽略
 	Provider string `mapstructure:"provider"`
 	APIKey   string `mapstructure:"api_key"`
 	CX       string `mapstructure:"cx"`
+	// Providers 按顺序尝试的搜索提供者，前一个出错、无结果或额度用尽时使用下一个
+	Providers []SearchProviderConfig `mapstructure:"providers"`
+	// Mode 多个提供者的使用方式：chain（默认，依次尝试）或 federated（并行查询后合并结果）
+	Mode      string                `mapstructure:"mode"`
+	Federated SearchFederatedConfig `mapstructure:"federated"`
+	// CircuitBreaker 每个搜索提供者各自的熔断器
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+}
+
+// SearchFederatedConfig 联合搜索配置
+type SearchFederatedConfig struct {
+	// Timeout 等待各提供者的时间，超时未返回的提供者不参与合并
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxResults 向每个提供者请求的结果数，也是合并后返回的结果数
+	MaxResults int `mapstructure:"max_results"`
+}
+
+// SearchProviderConfig 搜索提供者链中的一个提供者
+type SearchProviderConfig struct {
+	// Name 提供者名称：bocha、serper、tavily 或 google
+	Name   string `mapstructure:"name"`
+	APIKey string `mapstructure:"api_key"`
+	// APIKeyEnv 从该环境变量读取 API Key，优先于 APIKey
+	APIKeyEnv string `mapstructure:"api_key_env"`
+	// CX 仅用于 google
+	CX string `mapstructure:"cx"`
+}
+
+// ToolsConfig 工具注册表配置
+type ToolsConfig struct {
+	// Disabled 启动时默认禁用的工具名称，可通过管理接口重新启用
+	Disabled []string `mapstructure:"disabled"`
+	// Declared 在配置文件中声明的工具，无需编写 Go 代码
+	Declared []DeclaredToolConfig `mapstructure:"declared"`
+	// Scopes 调用各工具所需的权限范围，未列出的工具不做限制
+	Scopes []ToolScopeConfig `mapstructure:"scopes"`
+}
+
+// ToolScopeConfig 声明调用某个工具所需的权限范围
+// 使用列表而非 map 是因为 viper 会将 map 的键统一转为小写，破坏工具名
+type ToolScopeConfig struct {
+	Tool   string   `mapstructure:"tool"`
+	Scopes []string `mapstructure:"scopes"`
+}
+
+// DeclaredToolConfig 描述一个通过配置声明的工具
+type DeclaredToolConfig struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	// InputSchema 以 JSON 字符串书写的入参 JSON Schema
+	// 使用字符串是因为 viper 会将 map 的键统一转为小写，破坏属性名
+	InputSchema string `mapstructure:"input_schema"`
+	// Backend 工具调用绑定的后端
+	Backend BackendBindingConfig `mapstructure:"backend"`
+	// ResponseTemplate 以 text/template 渲染后端响应，为空时直接返回 JSON
+	ResponseTemplate string `mapstructure:"response_template"`
+	// Scopes 调用该工具所需的权限范围
+	Scopes []string `mapstructure:"scopes"`
+}
+
+// BackendBindingConfig 声明式工具的后端绑定
+type BackendBindingConfig struct {
+	// Type 为 grpc 或 http
+	Type string `mapstructure:"type"`
+	// GrpcMethod gRPC 方法全名，例如 mcp.extension.McpExtensionService/SearchDiary
+	GrpcMethod string `mapstructure:"grpc_method"`
+	// URL HTTP 接口地址
+	URL string `mapstructure:"url"`
+	// HTTPMethod HTTP 方法，默认为 POST；GET 请求的参数以 query 形式传递
+	HTTPMethod string `mapstructure:"http_method"`
+	// Headers 附加的 HTTP 请求头
+	Headers map[string]string `mapstructure:"headers"`
+	// Timeout 调用超时时间，默认 10s
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// CORSConfig 跨域策略与 Origin 校验
+type CORSConfig struct {
+	// AllowedOrigins 允许的来源，如 https://app.example.com；支持 "*"、子域名通配 "https://*.example.com"
+	// 与端口通配 "http://localhost:*"。为空时拒绝所有携带 Origin 的浏览器请求，不携带 Origin 的客户端不受影响
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// AllowedHeaders 预检响应中允许的请求头
+	AllowedHeaders []string `mapstructure:"allowed_headers"`
+	// ExposedHeaders 允许浏览器脚本读取的响应头
+	ExposedHeaders []string `mapstructure:"exposed_headers"`
+	// AllowCredentials 是否允许携带 Cookie 等凭据
+	AllowCredentials bool `mapstructure:"allow_credentials"`
+	// MaxAge 预检结果的缓存时间
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// ResourcesConfig 将日记与记忆暴露为 MCP 资源 (diary://{diaryId}、memory://{sourceId})
+type ResourcesConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ListLimit resources/list 中每类资源最多列出的条数
+	ListLimit int `mapstructure:"list_limit"`
+	// PollInterval 检查被订阅资源是否变化的间隔
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+}
+
+// AuthConfig MCP 端点的 API Key 认证配置
+type AuthConfig struct {
+	// Enabled 为 false 时不校验 API Key，仅将其透传给后端
+	Enabled bool `mapstructure:"enabled"`
+	// Stores 按顺序查询的密钥存储：static、file、backend
+	Stores []string `mapstructure:"stores"`
+	// Keys static 存储中的密钥
+	Keys []APIKeyConfig `mapstructure:"keys"`
+	// KeyFile file 存储使用的密钥文件，文件中只保存密钥的 SHA-256 摘要
+	KeyFile string `mapstructure:"key_file"`
+	// Backend backend 存储的配置，通过后端 ValidateApiKey RPC 校验
+	Backend AuthBackendConfig `mapstructure:"backend"`
+	// OAuth OAuth 2.1 资源服务器模式，校验授权服务器签发的 JWT 访问令牌
+	OAuth OAuthConfig `mapstructure:"oauth"`
+}
+
+// OAuthConfig 遵循 MCP 授权规范的资源服务器配置
+type OAuthConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Resource 本服务的资源标识，即客户端访问的 MCP 端点地址，例如 https://mcp.example.com/mcp
+	Resource string `mapstructure:"resource"`
+	// ResourceName 在资源元数据中展示的名称
+	ResourceName string `mapstructure:"resource_name"`
+	// AuthorizationServers 签发令牌的授权服务器地址
+	AuthorizationServers []string `mapstructure:"authorization_servers"`
+	// ScopesSupported 在资源元数据中公布的权限范围
+	ScopesSupported []string `mapstructure:"scopes_supported"`
+	// Issuer 期望的 iss 声明，为空时不校验
+	Issuer string `mapstructure:"issuer"`
+	// Audience 期望的 aud 声明，默认为 Resource
+	Audience string `mapstructure:"audience"`
+	// JWKSFile 与 JWKSURL 二选一，指定校验签名的公钥集合
+	JWKSFile string `mapstructure:"jwks_file"`
+	JWKSURL  string `mapstructure:"jwks_url"`
+	// JWKSRefresh 远程 JWKS 的刷新间隔
+	JWKSRefresh time.Duration `mapstructure:"jwks_refresh"`
+	// UserClaim 作为用户 ID 的声明，默认为 sub
+	UserClaim string `mapstructure:"user_claim"`
+	// ClockSkew 校验 exp/nbf 时允许的时钟偏差
+	ClockSkew time.Duration `mapstructure:"clock_skew"`
+}
+
+// APIKeyConfig 在配置文件中直接声明的 API Key
+type APIKeyConfig struct {
+	Key    string   `mapstructure:"key"`
+	UserID string   `mapstructure:"user_id"`
+	Scopes []string `mapstructure:"scopes"`
+}
+
+// AuthBackendConfig 后端密钥校验的缓存与超时配置
+type AuthBackendConfig struct {
+	// CacheTTL 有效密钥的缓存时间
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// NegativeCacheTTL 无效密钥的缓存时间
+	NegativeCacheTTL time.Duration `mapstructure:"negative_cache_ttl"`
+	// Timeout 单次校验的超时时间
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// AdminConfig 管理接口配置
+type AdminConfig struct {
+	// APIKey 访问 /admin 接口所需的密钥，为空时不开放管理接口
+	APIKey string `mapstructure:"api_key"`
 }
 
 type LogConfig struct {
@@ -30,7 +248,124 @@ type LogConfig struct {
 }
 
 type GrpcConfig struct {
+	// BackendTarget 默认后端集群的地址，也可以是返回多条记录的 DNS 名称，如 dns:///java-backend:9090
 	BackendTarget string `mapstructure:"backend_target"`
+	// Targets 默认后端集群的多个副本地址，配置后忽略 BackendTarget
+	Targets []string `mapstructure:"targets"`
+	// LoadBalancing 在副本间分配调用的策略：round_robin（默认）或 pick_first
+	LoadBalancing string `mapstructure:"load_balancing"`
+	// OutlierDetection 暂时摘除连续失败的副本
+	OutlierDetection OutlierDetectionConfig `mapstructure:"outlier_detection"`
+	// CircuitBreaker 每个后端集群各自的熔断器
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	// Clusters 默认集群之外的后端集群
+	Clusters []BackendClusterConfig `mapstructure:"clusters"`
+	// Routes 将工具或 RPC 方法路由到指定集群，未匹配的调用使用默认集群
+	Routes []BackendRouteConfig `mapstructure:"routes"`
+
+	TLS         GrpcTLSConfig     `mapstructure:"tls"`
+	Token       GrpcTokenConfig   `mapstructure:"token"`
+	Retry       GrpcRetryConfig   `mapstructure:"retry"`
+	Keepalive   KeepaliveConfig   `mapstructure:"keepalive"`
+	HealthCheck HealthCheckConfig `mapstructure:"health_check"`
+	// ReconnectMaxBackoff 连接断开后重连的最大退避时间
+	ReconnectMaxBackoff time.Duration    `mapstructure:"reconnect_max_backoff"`
+	Reflection          ReflectionConfig `mapstructure:"reflection"`
+	ToolSync            ToolSyncConfig   `mapstructure:"tool_sync"`
+}
+
+// GrpcRetryConfig 后端返回 UNAVAILABLE 时的重试策略
+type GrpcRetryConfig struct {
+	// MaxAttempts 含首次调用在内的最大尝试次数，小于 2 时不重试
+	MaxAttempts       int           `mapstructure:"max_attempts"`
+	InitialBackoff    time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff        time.Duration `mapstructure:"max_backoff"`
+	BackoffMultiplier float64       `mapstructure:"backoff_multiplier"`
+}
+
+// KeepaliveConfig 连接空闲时的 keepalive 探测
+type KeepaliveConfig struct {
+	// Time 连接空闲多久后发送 ping
+	Time time.Duration `mapstructure:"time"`
+	// Timeout 等待 ping 响应的时间，超时则认为连接已断开
+	Timeout time.Duration `mapstructure:"timeout"`
+	// PermitWithoutStream 没有进行中的调用时是否也发送 ping
+	PermitWithoutStream bool `mapstructure:"permit_without_stream"`
+}
+
+// HealthCheckConfig 基于 grpc.health.v1 的客户端健康检查
+type HealthCheckConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ServiceName 检查的服务名，为空表示后端整体的健康状态
+	ServiceName string `mapstructure:"service_name"`
+}
+
+// BackendClusterConfig 一组提供相同服务的后端副本
+type BackendClusterConfig struct {
+	Name    string   `mapstructure:"name"`
+	Targets []string `mapstructure:"targets"`
+	// LoadBalancing 为空时沿用 grpc.load_balancing
+	LoadBalancing string `mapstructure:"load_balancing"`
+}
+
+// BackendRouteConfig 将一个工具或 RPC 方法路由到指定集群，Tool 与 Method 二选一
+type BackendRouteConfig struct {
+	Tool string `mapstructure:"tool"`
+	// Method 形如 "pkg.Service/Method"
+	Method  string `mapstructure:"method"`
+	Cluster string `mapstructure:"cluster"`
+}
+
+// OutlierDetectionConfig 副本连续失败达到阈值后将其摘除一段时间，
+// 再次被摘除时摘除时间按次数递增，直到 MaxEjectionTime
+type OutlierDetectionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ConsecutiveFailures 触发摘除的连续失败次数（UNAVAILABLE、DEADLINE_EXCEEDED 等）
+	ConsecutiveFailures int           `mapstructure:"consecutive_failures"`
+	BaseEjectionTime    time.Duration `mapstructure:"base_ejection_time"`
+	MaxEjectionTime     time.Duration `mapstructure:"max_ejection_time"`
+	// MaxEjectionPercent 同一集群中最多可同时摘除的副本比例，至少保留一个副本
+	MaxEjectionPercent int `mapstructure:"max_ejection_percent"`
+}
+
+// GrpcTLSConfig 后端连接的 TLS/mTLS 配置
+type GrpcTLSConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CAFile 校验后端证书的 CA，为空时使用系统根证书
+	CAFile string `mapstructure:"ca_file"`
+	// CertFile 与 KeyFile 为 mTLS 的客户端证书，需同时配置
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// ServerName 覆盖校验证书时使用的服务名，默认取自 backend_target
+	ServerName string `mapstructure:"server_name"`
+}
+
+// GrpcTokenConfig 随每次调用发送的服务令牌，用于后端识别本服务
+type GrpcTokenConfig struct {
+	// Value 令牌值，为空时不发送；建议通过环境变量 GRPC_TOKEN_VALUE 注入
+	Value string `mapstructure:"value"`
+	// Header 承载令牌的 metadata 键，默认为 authorization，此时以 "Bearer <token>" 发送
+	Header string `mapstructure:"header"`
+	// AllowInsecure 允许在未开启 TLS 的连接上发送令牌，仅用于本机或可信网络
+	AllowInsecure bool `mapstructure:"allow_insecure"`
+}
+
+// ToolSyncConfig 定期通过 McpExtensionService.ListTools 同步后端声明的工具
+type ToolSyncConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Interval 同步间隔
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// ReflectionConfig 基于 gRPC server reflection 自动暴露后端方法为 MCP 工具
+type ReflectionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Allow 允许暴露的方法白名单，形如 "pkg.Service/Method" 或 "pkg.Service/*"
+	Allow []string `mapstructure:"allow"`
+	// ToolPrefix 自动生成的工具名前缀
+	ToolPrefix string `mapstructure:"tool_prefix"`
+	// Timeout 启动时反射发现的超时时间
+	Timeout time.Duration `mapstructure:"timeout"`
 }
 
 func Load() (*MCPConfig, error) {
@@ -45,8 +380,79 @@ func Load() (*MCPConfig, error) {
 	// 设置默认值
 	v.SetDefault("server.port", 11611)
 	v.SetDefault("server.env", "dev")
+	v.SetDefault("server.shutdown_timeout", "30s")
+	v.SetDefault("server.request_timeout", "30s")
+	v.SetDefault("server.health_port", 0)
+	v.SetDefault("server.trusted_proxies", []string{})
+	v.SetDefault("server.tls.enabled", false)
+	v.SetDefault("server.tls.client_auth", "none")
+	v.SetDefault("streamable.json_response", false)
+	v.SetDefault("streamable.stateless", false)
+	v.SetDefault("streamable.session_timeout", "30m")
+	v.SetDefault("cors.allowed_origins", []string{})
+	v.SetDefault("cors.allowed_headers", []string{"Content-Type", "Accept", "Authorization", "X-API-Key", "Mcp-Session-Id", "Mcp-Protocol-Version", "Last-Event-ID", "X-Request-Id", "traceparent", "tracestate"})
+	v.SetDefault("cors.exposed_headers", []string{"Mcp-Session-Id", "WWW-Authenticate", "X-Request-Id"})
+	v.SetDefault("cors.allow_credentials", false)
+	v.SetDefault("cors.max_age", "10m")
 	v.SetDefault("search.provider", "bocha")
+	v.SetDefault("search.mode", "chain")
+	v.SetDefault("search.federated.timeout", "5s")
+	v.SetDefault("search.federated.max_results", 5)
+	v.SetDefault("search.circuit_breaker.enabled", true)
+	v.SetDefault("search.circuit_breaker.consecutive_failures", 3)
+	v.SetDefault("search.circuit_breaker.slow_call_threshold", "8s")
+	v.SetDefault("search.circuit_breaker.open_timeout", "1m")
+	v.SetDefault("search.circuit_breaker.half_open_max_calls", 1)
 	v.SetDefault("grpc.backend_target", "localhost:9090")
+	v.SetDefault("grpc.targets", []string{})
+	v.SetDefault("grpc.load_balancing", "round_robin")
+	v.SetDefault("grpc.outlier_detection.enabled", true)
+	v.SetDefault("grpc.outlier_detection.consecutive_failures", 5)
+	v.SetDefault("grpc.outlier_detection.base_ejection_time", "30s")
+	v.SetDefault("grpc.outlier_detection.max_ejection_time", "5m")
+	v.SetDefault("grpc.outlier_detection.max_ejection_percent", 50)
+	v.SetDefault("grpc.circuit_breaker.enabled", true)
+	v.SetDefault("grpc.circuit_breaker.consecutive_failures", 5)
+	v.SetDefault("grpc.circuit_breaker.slow_call_threshold", "10s")
+	v.SetDefault("grpc.circuit_breaker.open_timeout", "30s")
+	v.SetDefault("grpc.circuit_breaker.half_open_max_calls", 1)
+	v.SetDefault("grpc.tls.enabled", false)
+	v.SetDefault("grpc.token.value", "")
+	v.SetDefault("grpc.token.header", "authorization")
+	v.SetDefault("grpc.token.allow_insecure", false)
+	v.SetDefault("grpc.retry.max_attempts", 3)
+	v.SetDefault("grpc.retry.initial_backoff", "100ms")
+	v.SetDefault("grpc.retry.max_backoff", "1s")
+	v.SetDefault("grpc.retry.backoff_multiplier", 2.0)
+	v.SetDefault("grpc.keepalive.time", "30s")
+	v.SetDefault("grpc.keepalive.timeout", "10s")
+	v.SetDefault("grpc.keepalive.permit_without_stream", false)
+	v.SetDefault("grpc.health_check.enabled", true)
+	v.SetDefault("grpc.health_check.service_name", "")
+	v.SetDefault("grpc.reconnect_max_backoff", "30s")
+	v.SetDefault("grpc.reflection.enabled", false)
+	v.SetDefault("grpc.reflection.allow", []string{})
+	v.SetDefault("grpc.reflection.tool_prefix", "")
+	v.SetDefault("grpc.reflection.timeout", "5s")
+	v.SetDefault("grpc.tool_sync.enabled", false)
+	v.SetDefault("grpc.tool_sync.interval", "1m")
+	v.SetDefault("tools.disabled", []string{})
+	v.SetDefault("resources.enabled", true)
+	v.SetDefault("resources.list_limit", 20)
+	v.SetDefault("resources.poll_interval", "1m")
+	v.SetDefault("prompts.dir", "prompts")
+	v.SetDefault("auth.enabled", false)
+	v.SetDefault("auth.stores", []string{"static"})
+	v.SetDefault("auth.backend.cache_ttl", "5m")
+	v.SetDefault("auth.backend.negative_cache_ttl", "30s")
+	v.SetDefault("auth.backend.timeout", "3s")
+	v.SetDefault("auth.oauth.enabled", false)
+	v.SetDefault("auth.oauth.jwks_refresh", "10m")
+	v.SetDefault("auth.oauth.user_claim", "sub")
+	v.SetDefault("auth.oauth.clock_skew", "1m")
+	v.SetDefault("rate_limit.enabled", false)
+	v.SetDefault("rate_limit.store", "memory")
+	v.SetDefault("admin.api_key", "")
 
 	// 日志级别默认值
 	v.SetDefault("log.level", "debug")
@@ -69,5 +475,227 @@ func Load() (*MCPConfig, error) {
 		return nil, fmt.Errorf("无法将解析出的配置映射为结构体: %w", err)
 	}
 
+	if err := validateDeclaredTools(cfg.Tools.Declared); err != nil {
+		return nil, err
+	}
+	if cfg.Grpc.ToolSync.Enabled && cfg.Grpc.ToolSync.Interval <= 0 {
+		return nil, fmt.Errorf("grpc.tool_sync.interval 必须大于 0")
+	}
+	if cfg.Resources.Enabled && cfg.Resources.PollInterval <= 0 {
+		return nil, fmt.Errorf("resources.poll_interval 必须大于 0")
+	}
+	if cfg.Auth.Enabled && len(cfg.Auth.Stores) == 0 {
+		return nil, fmt.Errorf("auth.stores 不能为空")
+	}
+	if err := validateBackends(&cfg.Grpc); err != nil {
+		return nil, err
+	}
+	if t := cfg.Grpc.TLS; t.Enabled && (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, fmt.Errorf("grpc.tls.cert_file 与 grpc.tls.key_file 需同时配置")
+	}
+	if t := cfg.Grpc.Token; t.Value != "" && !cfg.Grpc.TLS.Enabled && !t.AllowInsecure {
+		return nil, fmt.Errorf("grpc.token 需要开启 grpc.tls，或显式设置 grpc.token.allow_insecure")
+	}
+	if err := normalizeSearch(&cfg.Search); err != nil {
+		return nil, err
+	}
+	if err := validateBreaker("grpc.circuit_breaker", cfg.Grpc.CircuitBreaker); err != nil {
+		return nil, err
+	}
+	if err := validateBreaker("search.circuit_breaker", cfg.Search.CircuitBreaker); err != nil {
+		return nil, err
+	}
+	if err := validateServerTLS(&cfg.Server.TLS); err != nil {
+		return nil, err
+	}
+	if cfg.Server.HealthPort == cfg.Server.Port {
+		return nil, fmt.Errorf("server.health_port 不能与 server.port 相同")
+	}
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("server.trusted_proxies: %q 不是合法的 IP 或 CIDR", proxy)
+		}
+	}
+	if err := validateOAuth(&cfg.Auth.OAuth); err != nil {
+		return nil, err
+	}
+	if err := validateRateLimit(&cfg.RateLimit); err != nil {
+		return nil, err
+	}
+	if err := loadPrompts(&cfg.Prompts); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
+
+// normalizeSearch 将单个提供者的旧配置转换为只有一个提供者的链，解析各提供者的 API Key 并校验搜索模式
+func normalizeSearch(s *SearchConfig) error {
+	if len(s.Providers) == 0 && s.Provider != "" {
+		s.Providers = []SearchProviderConfig{{Name: s.Provider, APIKey: s.APIKey, CX: s.CX}}
+	}
+	switch s.Mode {
+	case "chain":
+	case "federated":
+		if s.Federated.Timeout <= 0 || s.Federated.MaxResults <= 0 {
+			return fmt.Errorf("search.federated.timeout 与 search.federated.max_results 必须大于 0")
+		}
+	default:
+		return fmt.Errorf("不支持的 search.mode %q", s.Mode)
+	}
+	seen := make(map[string]bool, len(s.Providers))
+	for i := range s.Providers {
+		p := &s.Providers[i]
+		p.Name = strings.ToLower(p.Name)
+		switch p.Name {
+		case "bocha", "serper", "tavily", "google":
+		default:
+			return fmt.Errorf("不支持的搜索提供者 %q", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("search.providers 中提供者 %s 重复", p.Name)
+		}
+		seen[p.Name] = true
+		if p.APIKeyEnv != "" {
+			p.APIKey = os.Getenv(p.APIKeyEnv)
+		}
+	}
+	return nil
+}
+
+func validateBackends(g *GrpcConfig) error {
+	validPolicy := func(p string) bool { return p == "round_robin" || p == "pick_first" }
+	if !validPolicy(g.LoadBalancing) {
+		return fmt.Errorf("不支持的 grpc.load_balancing %q", g.LoadBalancing)
+	}
+	if len(g.Targets) == 0 && g.BackendTarget == "" {
+		return fmt.Errorf("grpc.backend_target 与 grpc.targets 不能同时为空")
+	}
+	clusters := map[string]bool{"default": true}
+	for i := range g.Clusters {
+		c := &g.Clusters[i]
+		if c.Name == "" || clusters[c.Name] {
+			return fmt.Errorf("grpc.clusters[%d] 的名称为空或重复", i)
+		}
+		clusters[c.Name] = true
+		if len(c.Targets) == 0 {
+			return fmt.Errorf("grpc.clusters.%s 缺少 targets", c.Name)
+		}
+		if c.LoadBalancing == "" {
+			c.LoadBalancing = g.LoadBalancing
+		}
+		if !validPolicy(c.LoadBalancing) {
+			return fmt.Errorf("不支持的 grpc.clusters.%s.load_balancing %q", c.Name, c.LoadBalancing)
+		}
+	}
+	for i, r := range g.Routes {
+		if (r.Tool == "") == (r.Method == "") {
+			return fmt.Errorf("grpc.routes[%d] 需要且只能指定 tool 或 method 之一", i)
+		}
+		if !clusters[r.Cluster] {
+			return fmt.Errorf("grpc.routes[%d] 引用了不存在的集群 %q", i, r.Cluster)
+		}
+	}
+	if o := g.OutlierDetection; o.Enabled && o.ConsecutiveFailures < 1 {
+		return fmt.Errorf("grpc.outlier_detection.consecutive_failures 必须大于 0")
+	}
+	return nil
+}
+
+func validateServerTLS(t *ServerTLSConfig) error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.CertFile == "" || t.KeyFile == "" {
+		return fmt.Errorf("server.tls.cert_file 与 server.tls.key_file 不能为空")
+	}
+	switch t.ClientAuth {
+	case "", "none":
+		t.ClientAuth = "none"
+	case "verify_if_given", "require":
+		if t.ClientCAFile == "" {
+			return fmt.Errorf("server.tls.client_auth 为 %s 时 client_ca_file 不能为空", t.ClientAuth)
+		}
+	default:
+		return fmt.Errorf("不支持的 server.tls.client_auth %q", t.ClientAuth)
+	}
+	for i := range t.ClientPrincipals {
+		p := &t.ClientPrincipals[i]
+		if p.CommonName == "" {
+			return fmt.Errorf("server.tls.client_principals[%d] 缺少 common_name", i)
+		}
+		if p.UserID == "" {
+			p.UserID = p.CommonName
+		}
+	}
+	return nil
+}
+
+// validateOAuth 校验资源服务器配置，并补全默认值
+func validateOAuth(o *OAuthConfig) error {
+	if !o.Enabled {
+		return nil
+	}
+	if o.Resource == "" {
+		return fmt.Errorf("auth.oauth.resource 不能为空")
+	}
+	if (o.JWKSFile == "") == (o.JWKSURL == "") {
+		return fmt.Errorf("auth.oauth.jwks_file 与 auth.oauth.jwks_url 必须且只能配置一个")
+	}
+	if o.JWKSURL != "" && o.JWKSRefresh <= 0 {
+		return fmt.Errorf("auth.oauth.jwks_refresh 必须大于 0")
+	}
+	if o.Audience == "" {
+		o.Audience = o.Resource
+	}
+	return nil
+}
+
+// validateDeclaredTools 校验声明式工具配置，并补全默认值
+func validateDeclaredTools(tools []DeclaredToolConfig) error {
+	seen := make(map[string]bool, len(tools))
+	for i := range tools {
+		t := &tools[i]
+		if t.Name == "" {
+			return fmt.Errorf("tools.declared[%d]: name 不能为空", i)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("tools.declared: 工具 %s 重复声明", t.Name)
+		}
+		seen[t.Name] = true
+
+		if t.InputSchema == "" {
+			t.InputSchema = `{"type":"object"}`
+		}
+		var schema map[string]any
+		if err := json.Unmarshal([]byte(t.InputSchema), &schema); err != nil {
+			return fmt.Errorf("tools.declared[%s]: input_schema 不是合法的 JSON: %w", t.Name, err)
+		}
+		if schema["type"] != "object" {
+			return fmt.Errorf("tools.declared[%s]: input_schema 的 type 必须为 object", t.Name)
+		}
+
+		b := &t.Backend
+		b.Type = strings.ToLower(b.Type)
+		switch b.Type {
+		case "grpc":
+			if b.GrpcMethod == "" {
+				return fmt.Errorf("tools.declared[%s]: grpc 绑定需要 grpc_method", t.Name)
+			}
+		case "http":
+			if b.URL == "" {
+				return fmt.Errorf("tools.declared[%s]: http 绑定需要 url", t.Name)
+			}
+			if b.HTTPMethod == "" {
+				b.HTTPMethod = "POST"
+			}
+			b.HTTPMethod = strings.ToUpper(b.HTTPMethod)
+		default:
+			return fmt.Errorf("tools.declared[%s]: 不支持的 backend.type %q", t.Name, b.Type)
+		}
+		if b.Timeout <= 0 {
+			b.Timeout = 10 * time.Second
+		}
+	}
+	return nil
+}
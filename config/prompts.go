@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// PromptsConfig 提示词模板库配置
+type PromptsConfig struct {
+	// Dir 存放提示词模板文件 (*.yaml、*.yml、*.json) 的目录，每个文件定义一个模板；目录不存在时忽略
+	Dir string `mapstructure:"dir"`
+	// Templates 直接在配置文件中定义的提示词模板，与 Dir 中的模板合并
+	Templates []PromptTemplateConfig `mapstructure:"templates"`
+}
+
+// PromptTemplateConfig 描述一个提示词模板
+type PromptTemplateConfig struct {
+	Name        string `mapstructure:"name"`
+	Title       string `mapstructure:"title"`
+	Description string `mapstructure:"description"`
+	// Arguments 模板参数，客户端以字符串传入，服务端按 Type 校验并转换
+	Arguments []PromptArgumentConfig `mapstructure:"arguments"`
+	// Prefill 渲染前依次调用的工具，其结果可在消息模板中通过 .prefill.<as> 引用
+	Prefill []PromptPrefillConfig `mapstructure:"prefill"`
+	// Messages 以 text/template 书写的消息，参数可通过 .<参数名> 引用
+	Messages []PromptMessageConfig `mapstructure:"messages"`
+}
+
+// PromptArgumentConfig 描述提示词模板的一个参数
+type PromptArgumentConfig struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	// Type 为 string（默认）、integer、number、boolean、date (yyyy-MM-dd) 或 enum
+	Type     string   `mapstructure:"type"`
+	Required bool     `mapstructure:"required"`
+	Default  string   `mapstructure:"default"`
+	Enum     []string `mapstructure:"enum"`
+}
+
+// PromptPrefillConfig 描述一次预填充的工具调用
+type PromptPrefillConfig struct {
+	// Tool 调用的工具名称，例如 diarySearch、memorySearch
+	Tool string `mapstructure:"tool"`
+	// Arguments 以 text/template 书写、渲染结果为 JSON 对象的工具入参
+	// 使用字符串是因为 viper 会将 map 的键统一转为小写，破坏参数名
+	Arguments string `mapstructure:"arguments"`
+	// As 结果在消息模板中的名称
+	As string `mapstructure:"as"`
+}
+
+// PromptMessageConfig 描述提示词中的一条消息
+type PromptMessageConfig struct {
+	// Role 为 user（默认）或 assistant
+	Role string `mapstructure:"role"`
+	Text string `mapstructure:"text"`
+}
+
+var promptArgumentTypes = []string{"string", "integer", "number", "boolean", "date", "enum"}
+
+// loadPrompts 读取 Dir 中的模板文件并与配置中的模板合并，同时校验并补全默认值
+func loadPrompts(cfg *PromptsConfig) error {
+	if cfg.Dir != "" {
+		entries, err := os.ReadDir(cfg.Dir)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("无法读取提示词目录 %s: %w", cfg.Dir, err)
+		}
+		for _, e := range entries {
+			ext := strings.ToLower(filepath.Ext(e.Name()))
+			if e.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+				continue
+			}
+			path := filepath.Join(cfg.Dir, e.Name())
+			v := viper.New()
+			v.SetConfigFile(path)
+			if err := v.ReadInConfig(); err != nil {
+				return fmt.Errorf("无法读取提示词文件 %s: %w", path, err)
+			}
+			var t PromptTemplateConfig
+			if err := v.Unmarshal(&t); err != nil {
+				return fmt.Errorf("无法解析提示词文件 %s: %w", path, err)
+			}
+			cfg.Templates = append(cfg.Templates, t)
+		}
+	}
+	return validatePrompts(cfg.Templates)
+}
+
+// validatePrompts 校验提示词模板配置，并补全默认值
+func validatePrompts(templates []PromptTemplateConfig) error {
+	seen := make(map[string]bool, len(templates))
+	for i := range templates {
+		t := &templates[i]
+		if t.Name == "" {
+			return fmt.Errorf("prompts.templates[%d]: name 不能为空", i)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("prompts: 提示词 %s 重复定义", t.Name)
+		}
+		seen[t.Name] = true
+
+		if len(t.Messages) == 0 {
+			return fmt.Errorf("prompts[%s]: messages 不能为空", t.Name)
+		}
+		for j := range t.Messages {
+			m := &t.Messages[j]
+			m.Role = strings.ToLower(m.Role)
+			if m.Role == "" {
+				m.Role = "user"
+			}
+			if m.Role != "user" && m.Role != "assistant" {
+				return fmt.Errorf("prompts[%s]: 不支持的 role %q", t.Name, m.Role)
+			}
+		}
+
+		args := make(map[string]bool, len(t.Arguments))
+		for j := range t.Arguments {
+			a := &t.Arguments[j]
+			if a.Name == "" {
+				return fmt.Errorf("prompts[%s].arguments[%d]: name 不能为空", t.Name, j)
+			}
+			if a.Name == "prefill" || args[a.Name] {
+				return fmt.Errorf("prompts[%s]: 参数名 %s 重复或为保留字", t.Name, a.Name)
+			}
+			args[a.Name] = true
+
+			a.Type = strings.ToLower(a.Type)
+			if a.Type == "" {
+				a.Type = "string"
+			}
+			if !slices.Contains(promptArgumentTypes, a.Type) {
+				return fmt.Errorf("prompts[%s]: 参数 %s 的类型 %q 不受支持", t.Name, a.Name, a.Type)
+			}
+			if a.Type == "enum" && len(a.Enum) == 0 {
+				return fmt.Errorf("prompts[%s]: enum 参数 %s 需要 enum 取值列表", t.Name, a.Name)
+			}
+		}
+
+		for j, p := range t.Prefill {
+			if p.Tool == "" || p.As == "" {
+				return fmt.Errorf("prompts[%s].prefill[%d]: tool 与 as 不能为空", t.Name, j)
+			}
+		}
+	}
+	return nil
+}
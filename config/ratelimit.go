@@ -0,0 +1,75 @@
+package config
+
+import "fmt"
+
+// RateLimitConfig 按调用方与工具限流，并限制每日调用次数
+type RateLimitConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Store 计数存储，目前仅支持 memory（单实例）；多实例部署需实现 ratelimit.Store 接入共享存储
+	Store string `mapstructure:"store"`
+	// PerKey 每个调用方在 tools/call、resources/read、prompts/get 上共享的令牌桶
+	PerKey BucketConfig `mapstructure:"per_key"`
+	// DailyQuota 每个调用方每天可调用工具的次数，0 表示不限制
+	DailyQuota int64 `mapstructure:"daily_quota"`
+	// Tools 针对单个工具的额外限制，在 PerKey 之外按调用方分别计数
+	Tools []ToolRateLimitConfig `mapstructure:"tools"`
+}
+
+// BucketConfig 令牌桶参数，Rate 为 0 表示不限制
+type BucketConfig struct {
+	// Rate 每秒补充的令牌数
+	Rate float64 `mapstructure:"rate"`
+	// Burst 桶容量，即允许的突发请求数，默认与 Rate 相同（至少为 1）
+	Burst int `mapstructure:"burst"`
+}
+
+// ToolRateLimitConfig 单个工具的限流与每日配额
+type ToolRateLimitConfig struct {
+	Tool         string `mapstructure:"tool"`
+	BucketConfig `mapstructure:",squash"`
+	// DailyQuota 每个调用方每天可调用该工具的次数，0 表示不限制
+	DailyQuota int64 `mapstructure:"daily_quota"`
+}
+
+func validateRateLimit(r *RateLimitConfig) error {
+	if !r.Enabled {
+		return nil
+	}
+	if r.Store != "memory" {
+		return fmt.Errorf("不支持的限流存储 %q", r.Store)
+	}
+	if err := normalizeBucket("rate_limit.per_key", &r.PerKey); err != nil {
+		return err
+	}
+	if r.DailyQuota < 0 {
+		return fmt.Errorf("rate_limit.daily_quota 不能为负数")
+	}
+	seen := make(map[string]bool, len(r.Tools))
+	for i := range r.Tools {
+		t := &r.Tools[i]
+		if t.Tool == "" {
+			return fmt.Errorf("rate_limit.tools[%d] 缺少 tool", i)
+		}
+		if seen[t.Tool] {
+			return fmt.Errorf("rate_limit.tools 中工具 %s 重复", t.Tool)
+		}
+		seen[t.Tool] = true
+		if err := normalizeBucket("rate_limit.tools."+t.Tool, &t.BucketConfig); err != nil {
+			return err
+		}
+		if t.DailyQuota < 0 {
+			return fmt.Errorf("rate_limit.tools.%s.daily_quota 不能为负数", t.Tool)
+		}
+	}
+	return nil
+}
+
+func normalizeBucket(name string, b *BucketConfig) error {
+	if b.Rate < 0 || b.Burst < 0 {
+		return fmt.Errorf("%s 的 rate 与 burst 不能为负数", name)
+	}
+	if b.Rate > 0 && b.Burst == 0 {
+		b.Burst = max(int(b.Rate), 1)
+	}
+	return nil
+}
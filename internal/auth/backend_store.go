@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"mcp/config"
+	"mcp/internal/grpc"
+	"mcp/internal/reqctx"
+	pb "mcp/proto"
+)
+
+// maxCacheEntries 缓存条目超过该数量时清理已过期的条目
+const maxCacheEntries = 10000
+
+type cacheEntry struct {
+	principal *reqctx.Principal // 为 nil 表示密钥无效
+	expires   time.Time
+}
+
+// BackendKeyStore 通过后端 ValidateApiKey RPC 校验密钥，并按摘要缓存校验结果
+type BackendKeyStore struct {
+	cfg config.AuthBackendConfig
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewBackendKeyStore 创建基于后端 RPC 的密钥存储
+func NewBackendKeyStore(cfg config.AuthBackendConfig) *BackendKeyStore {
+	return &BackendKeyStore{cfg: cfg, cache: make(map[string]cacheEntry)}
+}
+
+func (s *BackendKeyStore) Lookup(ctx context.Context, key string) (*reqctx.Principal, error) {
+	hash := HashKey(key)
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.cache[hash]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		if entry.principal == nil {
+			return nil, ErrInvalidKey
+		}
+		return entry.principal, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	res, err := grpc.ValidateApiKey(ctx, &pb.ValidateApiKeyRequest{ApiKey: key})
+	if err != nil {
+		return nil, err
+	}
+
+	entry = cacheEntry{expires: now.Add(s.cfg.NegativeCacheTTL)}
+	if res.Valid {
+		entry = cacheEntry{
+			principal: &reqctx.Principal{UserID: res.UserId, Scopes: res.Scopes},
+			expires:   now.Add(s.cfg.CacheTTL),
+		}
+	}
+	s.store(hash, entry)
+
+	if entry.principal == nil {
+		return nil, ErrInvalidKey
+	}
+	return entry.principal, nil
+}
+
+func (s *BackendKeyStore) store(hash string, entry cacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= maxCacheEntries {
+		now := time.Now()
+		for k, e := range s.cache {
+			if now.After(e.expires) {
+				delete(s.cache, k)
+			}
+		}
+	}
+	s.cache[hash] = entry
+}
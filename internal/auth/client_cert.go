@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"crypto/x509"
+
+	"mcp/config"
+	"mcp/internal/reqctx"
+)
+
+// ClientCertPrincipals 将经过 mTLS 校验的客户端证书按主题 CN 映射为调用方身份
+type ClientCertPrincipals map[string]*reqctx.Principal
+
+// NewClientCertPrincipals 根据配置创建映射
+func NewClientCertPrincipals(cfgs []config.ClientCertPrincipalConfig) ClientCertPrincipals {
+	m := make(ClientCertPrincipals, len(cfgs))
+	for _, c := range cfgs {
+		m[c.CommonName] = &reqctx.Principal{UserID: c.UserID, Scopes: c.Scopes}
+	}
+	return m
+}
+
+// Lookup 返回证书对应的调用方身份；未配置的证书以 CN 作为用户 ID，不授予任何权限范围
+func (m ClientCertPrincipals) Lookup(cert *x509.Certificate) *reqctx.Principal {
+	cn := cert.Subject.CommonName
+	if p, ok := m[cn]; ok {
+		return p
+	}
+	return &reqctx.Principal{UserID: cn}
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp/internal/reqctx"
+	"mcp/pkg/log"
+
+	"github.com/spf13/viper"
+)
+
+// fileKeyEntry 是密钥文件中的一条记录，只保存密钥的 SHA-256 摘要
+type fileKeyEntry struct {
+	KeyHash string   `mapstructure:"key_hash"`
+	UserID  string   `mapstructure:"user_id"`
+	Scopes  []string `mapstructure:"scopes"`
+}
+
+// FileKeyStore 从 YAML/JSON 文件加载哈希后的密钥，文件修改后自动重新加载
+type FileKeyStore struct {
+	path string
+
+	mu      sync.RWMutex
+	modTime time.Time
+	keys    map[string]*reqctx.Principal
+}
+
+// NewFileKeyStore 加载密钥文件，文件格式为：
+//
+//	keys:
+//	  - key_hash: "<sha256 hex>"
+//	    user_id: "user-1"
+//	    scopes: ["diary:read"]
+func NewFileKeyStore(path string) (*FileKeyStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("auth.key_file 不能为空")
+	}
+	s := &FileKeyStore{path: path}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileKeyStore) reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("无法读取密钥文件 %s: %w", s.path, err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(s.path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("无法读取密钥文件 %s: %w", s.path, err)
+	}
+	var file struct {
+		Keys []fileKeyEntry `mapstructure:"keys"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return fmt.Errorf("无法解析密钥文件 %s: %w", s.path, err)
+	}
+
+	keys := make(map[string]*reqctx.Principal, len(file.Keys))
+	for i, k := range file.Keys {
+		hash := strings.ToLower(strings.TrimPrefix(k.KeyHash, "sha256:"))
+		if len(hash) != 64 {
+			return fmt.Errorf("密钥文件 %s 第 %d 条记录的 key_hash 不是合法的 SHA-256 摘要", s.path, i+1)
+		}
+		keys[hash] = &reqctx.Principal{UserID: k.UserID, Scopes: k.Scopes}
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.modTime = info.ModTime()
+	s.mu.Unlock()
+	return nil
+}
+
+// Lookup 查询密钥；文件发生变化时先重新加载，加载失败则继续使用旧的内容
+func (s *FileKeyStore) Lookup(ctx context.Context, key string) (*reqctx.Principal, error) {
+	if info, err := os.Stat(s.path); err == nil {
+		s.mu.RLock()
+		changed := !info.ModTime().Equal(s.modTime)
+		s.mu.RUnlock()
+		if changed {
+			if err := s.reload(); err != nil {
+				log.Warn("重新加载密钥文件失败", "path", s.path, "error", err)
+			} else {
+				log.Info("已重新加载密钥文件", "path", s.path)
+			}
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if p, ok := s.keys[HashKey(key)]; ok {
+		return p, nil
+	}
+	return nil, ErrInvalidKey
+}
@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"mcp/pkg/log"
+)
+
+// jwksMinRefresh 遇到未知 kid 时重新拉取 JWKS 的最小间隔，防止被伪造的 kid 放大请求
+const jwksMinRefresh = 30 * time.Second
+
+// jwk 是 JWKS 中的一个公钥 (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey 是解析后的公钥及其声明的算法
+type publicKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// keySet 从本地文件或 URL 加载 JWKS，按需刷新
+type keySet struct {
+	file   string
+	url    string
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      []publicKey
+	refreshed time.Time
+}
+
+func newKeySet(file, url string) *keySet {
+	return &keySet{file: file, url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// refresh 重新加载 JWKS，失败时保留旧的公钥
+func (s *keySet) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	s.refreshed = time.Now()
+	s.mu.Unlock()
+
+	var (
+		body []byte
+		err  error
+	)
+	if s.file != "" {
+		body, err = os.ReadFile(s.file)
+	} else {
+		body, err = s.fetch(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("无法加载 JWKS: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		return fmt.Errorf("无法解析 JWKS: %w", err)
+	}
+
+	keys := make([]publicKey, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Warn("忽略无法解析的 JWK", "kid", k.Kid, "error", err)
+			continue
+		}
+		keys = append(keys, publicKey{kid: k.Kid, alg: k.Alg, key: key})
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *keySet) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", s.url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// candidates 返回可能用于验证签名的公钥；kid 未知时最多每 jwksMinRefresh 重新加载一次
+func (s *keySet) candidates(ctx context.Context, kid string) []publicKey {
+	if found := s.match(kid); len(found) > 0 {
+		return found
+	}
+
+	s.mu.RLock()
+	stale := time.Since(s.refreshed) >= jwksMinRefresh
+	s.mu.RUnlock()
+	if stale {
+		if err := s.refresh(ctx); err != nil {
+			log.Warn("刷新 JWKS 失败", "error", err)
+		}
+	}
+	return s.match(kid)
+}
+
+func (s *keySet) match(kid string) []publicKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var found []publicKey
+	for _, k := range s.keys {
+		if kid == "" || k.kid == kid {
+			found = append(found, k)
+		}
+	}
+	return found
+}
+
+// run 按固定间隔刷新远程 JWKS，直到 ctx 被取消
+func (s *keySet) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.refresh(ctx); err != nil {
+				log.Warn("刷新 JWKS 失败", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid n: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid e: %w", err)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("invalid coordinate length")
+		}
+		point := append(append([]byte{4}, x...), y...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"mcp/config"
+	"mcp/internal/reqctx"
+)
+
+// JWTVerifier 以 OAuth 2.1 资源服务器的身份校验 JWT 访问令牌
+// 令牌不是 JWT 格式时返回 ErrInvalidKey，以便继续交给 API Key 存储处理
+type JWTVerifier struct {
+	cfg  config.OAuthConfig
+	keys *keySet
+}
+
+// NewJWTVerifier 创建 JWT 校验器并加载一次 JWKS
+// 使用远程 JWKS 时会在后台按 cfg.JWKSRefresh 定期刷新，直到 ctx 被取消
+func NewJWTVerifier(ctx context.Context, cfg config.OAuthConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{cfg: cfg, keys: newKeySet(cfg.JWKSFile, cfg.JWKSURL)}
+	if err := v.keys.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if cfg.JWKSFile == "" {
+		go v.keys.run(ctx, cfg.JWKSRefresh)
+	}
+	return v, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Lookup 校验令牌的签名、签发方、受众与有效期，并将 scope 映射为调用方的权限范围
+func (v *JWTVerifier) Lookup(ctx context.Context, token string) (*reqctx.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidKey
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidKey
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidKey)
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, k := range v.keys.candidates(ctx, header.Kid) {
+		if k.alg != "" && k.alg != header.Alg {
+			continue
+		}
+		if verifySignature(header.Alg, k.key, signed, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("%w: signature verification failed", ErrInvalidKey)
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidKey)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+
+	userID, _ := claims[v.cfg.UserClaim].(string)
+	return &reqctx.Principal{UserID: userID, Scopes: tokenScopes(claims)}, nil
+}
+
+func (v *JWTVerifier) checkClaims(claims map[string]any) error {
+	if v.cfg.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
+			return fmt.Errorf("unexpected issuer")
+		}
+	}
+
+	if v.cfg.Audience != "" {
+		var aud []string
+		switch a := claims["aud"].(type) {
+		case string:
+			aud = []string{a}
+		case []any:
+			for _, item := range a {
+				if s, ok := item.(string); ok {
+					aud = append(aud, s)
+				}
+			}
+		}
+		if !slices.Contains(aud, v.cfg.Audience) {
+			return fmt.Errorf("unexpected audience")
+		}
+	}
+
+	now := time.Now()
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return fmt.Errorf("token missing expiration")
+	}
+	if now.After(exp.Add(v.cfg.ClockSkew)) {
+		return fmt.Errorf("token expired")
+	}
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(v.cfg.ClockSkew).Before(nbf) {
+		return fmt.Errorf("token not yet valid")
+	}
+	return nil
+}
+
+// tokenScopes 读取 scope（空格分隔的字符串，RFC 8693）或 scp（字符串数组）声明
+func tokenScopes(claims map[string]any) []string {
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	var scopes []string
+	switch scp := claims["scp"].(type) {
+	case string:
+		scopes = strings.Fields(scp)
+	case []any:
+		for _, item := range scp {
+			if s, ok := item.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	return scopes
+}
+
+func numericDate(v any) (time.Time, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// verifySignature 校验 JWS 签名，仅支持非对称算法；none 与 HS* 一律拒绝
+func verifySignature(alg string, key crypto.PublicKey, signed, sig []byte) bool {
+	if len(alg) != 5 {
+		return false
+	}
+	var hash crypto.Hash
+	switch alg[len(alg)-3:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(pub, hash, digest, sig) == nil
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPSS(pub, hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return false
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	}
+	return false
+}
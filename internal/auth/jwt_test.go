@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mcp/config"
+)
+
+const (
+	testIssuer   = "https://auth.example.com"
+	testAudience = "https://mcp.example.com/mcp"
+)
+
+// jwksServer 提供可替换的 JWKS，并统计被拉取的次数
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{keys: make(map[string]*rsa.PrivateKey)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var set struct {
+			Keys []jwk `json:"keys"`
+		}
+		for kid, key := range s.keys {
+			set.Keys = append(set.Keys, jwk{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: "RS256",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// addKey 生成一个新的 RSA 密钥并加入 JWKS
+func (s *jwksServer) addKey(t *testing.T, kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	s.keys[kid] = key
+	s.mu.Unlock()
+	return key
+}
+
+func newTestVerifier(t *testing.T, jwksURL string) *JWTVerifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	v, err := NewJWTVerifier(ctx, config.OAuthConfig{
+		Issuer:      testIssuer,
+		Audience:    testAudience,
+		JWKSURL:     jwksURL,
+		JWKSRefresh: time.Hour,
+		UserClaim:   "sub",
+		ClockSkew:   time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func encodeSegment(t *testing.T, v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// signRS256 以 RS256 签发令牌
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	signed := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims() map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "alice",
+		"scope": "diary:read memory:read",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTVerifierAcceptsValidToken(t *testing.T) {
+	jwks := newJWKSServer(t)
+	key := jwks.addKey(t, "k1")
+	v := newTestVerifier(t, jwks.URL)
+
+	p, err := v.Lookup(context.Background(), signRS256(t, key, "k1", validClaims()))
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if p.UserID != "alice" || len(p.Scopes) != 2 || p.Scopes[0] != "diary:read" || p.Scopes[1] != "memory:read" {
+		t.Fatalf("principal = %+v", p)
+	}
+}
+
+func TestJWTVerifierRejectsUnsafeAlgorithms(t *testing.T) {
+	jwks := newJWKSServer(t)
+	key := jwks.addKey(t, "k1")
+	v := newTestVerifier(t, jwks.URL)
+	claims := encodeSegment(t, validClaims())
+
+	// alg=none：不带签名
+	none := encodeSegment(t, map[string]string{"alg": "none", "kid": "k1"}) + "." + claims + "."
+
+	// HS256：以公开的 RSA 公钥作为 HMAC 密钥伪造签名（算法混淆攻击）
+	hsSigned := encodeSegment(t, map[string]string{"alg": "HS256", "kid": "k1"}) + "." + claims
+	mac := hmac.New(sha256.New, key.PublicKey.N.Bytes())
+	mac.Write([]byte(hsSigned))
+	hs := hsSigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	for name, token := range map[string]string{"none": none, "HS256": hs} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := v.Lookup(context.Background(), token); !errors.Is(err, ErrInvalidKey) {
+				t.Fatalf("Lookup err = %v, want ErrInvalidKey", err)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierClaims(t *testing.T) {
+	jwks := newJWKSServer(t)
+	key := jwks.addKey(t, "k1")
+	v := newTestVerifier(t, jwks.URL)
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		modify func(map[string]any)
+		valid  bool
+	}{
+		{"wrong issuer", func(c map[string]any) { c["iss"] = "https://evil.example.com" }, false},
+		{"missing issuer", func(c map[string]any) { delete(c, "iss") }, false},
+		{"wrong audience", func(c map[string]any) { c["aud"] = "https://other.example.com" }, false},
+		{"audience list", func(c map[string]any) { c["aud"] = []string{"https://other.example.com", testAudience} }, true},
+		{"missing exp", func(c map[string]any) { delete(c, "exp") }, false},
+		{"expired within skew", func(c map[string]any) { c["exp"] = now.Add(-30 * time.Second).Unix() }, true},
+		{"expired beyond skew", func(c map[string]any) { c["exp"] = now.Add(-2 * time.Minute).Unix() }, false},
+		{"nbf within skew", func(c map[string]any) { c["nbf"] = now.Add(30 * time.Second).Unix() }, true},
+		{"nbf beyond skew", func(c map[string]any) { c["nbf"] = now.Add(2 * time.Minute).Unix() }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.modify(claims)
+			_, err := v.Lookup(context.Background(), signRS256(t, key, "k1", claims))
+			if tt.valid && err != nil {
+				t.Fatalf("Lookup: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidKey) {
+				t.Fatalf("Lookup err = %v, want ErrInvalidKey", err)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierRefreshesOnUnknownKid(t *testing.T) {
+	jwks := newJWKSServer(t)
+	jwks.addKey(t, "k1")
+	v := newTestVerifier(t, jwks.URL)
+	if n := jwks.fetches.Load(); n != 1 {
+		t.Fatalf("initial fetches = %d, want 1", n)
+	}
+
+	// 授权服务器轮换密钥后，新 kid 签发的令牌在最小刷新间隔内不会触发重新拉取
+	rotated := jwks.addKey(t, "k2")
+	token := signRS256(t, rotated, "k2", validClaims())
+	if _, err := v.Lookup(context.Background(), token); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("Lookup within min refresh err = %v, want ErrInvalidKey", err)
+	}
+	if n := jwks.fetches.Load(); n != 1 {
+		t.Fatalf("fetches within min refresh = %d, want 1", n)
+	}
+
+	// 超过最小刷新间隔后，未知 kid 触发一次重新拉取
+	v.keys.mu.Lock()
+	v.keys.refreshed = time.Now().Add(-jwksMinRefresh)
+	v.keys.mu.Unlock()
+	if _, err := v.Lookup(context.Background(), token); err != nil {
+		t.Fatalf("Lookup after refresh: %v", err)
+	}
+	if n := jwks.fetches.Load(); n != 2 {
+		t.Fatalf("fetches after refresh = %d, want 2", n)
+	}
+
+	// 已知的 kid 不再拉取
+	if _, err := v.Lookup(context.Background(), token); err != nil {
+		t.Fatalf("Lookup with known kid: %v", err)
+	}
+	if n := jwks.fetches.Load(); n != 2 {
+		t.Fatalf("fetches with known kid = %d, want 2", n)
+	}
+}
+
+func TestJWTVerifierIgnoresNonJWT(t *testing.T) {
+	jwks := newJWKSServer(t)
+	jwks.addKey(t, "k1")
+	v := newTestVerifier(t, jwks.URL)
+
+	// 非 JWT 格式的凭据交给后续的 API Key 存储处理
+	if _, err := v.Lookup(context.Background(), "plain-api-key"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("Lookup err = %v, want ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"net/url"
+	"path"
+
+	"mcp/config"
+
+	"github.com/modelcontextprotocol/go-sdk/oauthex"
+)
+
+// wellKnownResourcePath 是受保护资源元数据的 well-known 路径 (RFC 9728)
+const wellKnownResourcePath = "/.well-known/oauth-protected-resource"
+
+// ProtectedResourceMetadata 返回本服务作为受保护资源公布的元数据
+func ProtectedResourceMetadata(cfg config.OAuthConfig) *oauthex.ProtectedResourceMetadata {
+	return &oauthex.ProtectedResourceMetadata{
+		Resource:               cfg.Resource,
+		ResourceName:           cfg.ResourceName,
+		AuthorizationServers:   cfg.AuthorizationServers,
+		ScopesSupported:        cfg.ScopesSupported,
+		BearerMethodsSupported: []string{"header"},
+	}
+}
+
+// ResourceMetadataPaths 返回元数据的服务路径：资源标识带路径时，
+// RFC 9728 要求将 well-known 路径插入到主机与路径之间，同时保留根路径以兼容旧客户端
+func ResourceMetadataPaths(resource string) []string {
+	paths := []string{wellKnownResourcePath}
+	if u, err := url.Parse(resource); err == nil && u.Path != "" && u.Path != "/" {
+		paths = append(paths, path.Join(wellKnownResourcePath, u.Path))
+	}
+	return paths
+}
+
+// ResourceMetadataURL 返回在 WWW-Authenticate 中告知客户端的元数据地址
+func ResourceMetadataURL(resource string) string {
+	u, err := url.Parse(resource)
+	if err != nil {
+		return ""
+	}
+	paths := ResourceMetadataPaths(resource)
+	u.Path = paths[len(paths)-1]
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"mcp/config"
+	"mcp/internal/reqctx"
+)
+
+// ErrInvalidKey 表示 API Key 不存在、已吊销或已过期
+var ErrInvalidKey = errors.New("invalid api key")
+
+// KeyStore 根据 API Key 解析调用方身份
+// 密钥无效时返回 ErrInvalidKey，其它错误表示存储本身不可用
+type KeyStore interface {
+	Lookup(ctx context.Context, key string) (*reqctx.Principal, error)
+}
+
+// ChainKeyStore 依次查询多个存储，返回第一个识别该密钥的结果
+type ChainKeyStore []KeyStore
+
+func (c ChainKeyStore) Lookup(ctx context.Context, key string) (*reqctx.Principal, error) {
+	for _, store := range c {
+		p, err := store.Lookup(ctx, key)
+		if errors.Is(err, ErrInvalidKey) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrInvalidKey
+}
+
+// StaticKeyStore 是在配置文件中直接声明的密钥，按摘要索引
+type StaticKeyStore map[string]*reqctx.Principal
+
+// NewStaticKeyStore 根据配置创建静态密钥存储
+func NewStaticKeyStore(keys []config.APIKeyConfig) StaticKeyStore {
+	store := make(StaticKeyStore, len(keys))
+	for _, k := range keys {
+		store[HashKey(k.Key)] = &reqctx.Principal{UserID: k.UserID, Scopes: k.Scopes}
+	}
+	return store
+}
+
+func (s StaticKeyStore) Lookup(ctx context.Context, key string) (*reqctx.Principal, error) {
+	if p, ok := s[HashKey(key)]; ok {
+		return p, nil
+	}
+	return nil, ErrInvalidKey
+}
+
+// NewKeyStore 组装密钥存储：开启 OAuth 时优先校验 JWT 访问令牌，
+// 开启 API Key 认证时再按 auth.stores 的顺序查询
+func NewKeyStore(ctx context.Context, cfg config.AuthConfig) (KeyStore, error) {
+	var chain ChainKeyStore
+	if cfg.OAuth.Enabled {
+		verifier, err := NewJWTVerifier(ctx, cfg.OAuth)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, verifier)
+	}
+	if !cfg.Enabled {
+		return chain, nil
+	}
+	for _, name := range cfg.Stores {
+		switch name {
+		case "static":
+			chain = append(chain, NewStaticKeyStore(cfg.Keys))
+		case "file":
+			store, err := NewFileKeyStore(cfg.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, store)
+		case "backend":
+			chain = append(chain, NewBackendKeyStore(cfg.Backend))
+		default:
+			return nil, fmt.Errorf("不支持的密钥存储 %q", name)
+		}
+	}
+	return chain, nil
+}
+
+// HashKey 返回 API Key 的 SHA-256 摘要（十六进制），密钥文件中只保存该摘要
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,244 @@
+// Package breaker 为搜索提供者与后端 RPC 等外部依赖提供熔断器
+// 依赖持续失败或变慢时快速失败，避免每次工具调用都等待到超时
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"mcp/config"
+)
+
+// State 熔断器状态
+type State int
+
+const (
+	// StateClosed 正常放行调用
+	StateClosed State = iota
+	// StateHalfOpen 熔断超时后放行少量探测调用
+	StateHalfOpen
+	// StateOpen 熔断中，调用立即失败
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateHalfOpen:
+		return "half_open"
+	case StateOpen:
+		return "open"
+	}
+	return "closed"
+}
+
+// ErrOpen 表示调用因熔断被拒绝，可用 errors.Is 判断
+var ErrOpen = errors.New("circuit breaker is open")
+
+// OpenError 是熔断期间返回的错误
+type OpenError struct {
+	// Name 熔断器名称
+	Name string
+	// RetryAfter 距离开始探测恢复的时间
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s 暂不可用（已熔断），请 %s 后重试", e.Name, e.RetryAfter.Round(time.Second))
+}
+
+// Is 使 errors.Is(err, ErrOpen) 成立
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// Breaker 按连续失败次数熔断的熔断器，并发安全
+type Breaker struct {
+	name string
+	cfg  config.CircuitBreakerConfig
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int       // 连续失败次数
+	openedAt time.Time // 最近一次熔断的时间
+	probes   int       // 半开状态下正在进行的探测调用
+	halfOpen uint64    // 进入半开状态的次数，用于识别探测调用属于哪一轮探测
+	stats    Stats
+}
+
+// Call 是一次已放行的调用，必须以调用结果调用一次 Done 或 Release
+type Call struct {
+	b *Breaker
+	// probe 为 true 表示该调用是第 round 轮半开探测放行的探测调用
+	probe bool
+	round uint64
+}
+
+// Stats 熔断器的累计计数，用于监控
+type Stats struct {
+	// Calls 放行的调用次数
+	Calls uint64
+	// Failures 失败的调用次数，包括慢调用
+	Failures uint64
+	// SlowCalls 耗时超过阈值的调用次数
+	SlowCalls uint64
+	// Rejected 因熔断被拒绝的调用次数
+	Rejected uint64
+	// Opened 进入熔断状态的次数
+	Opened uint64
+}
+
+// Snapshot 是熔断器在某一时刻的状态
+type Snapshot struct {
+	Name     string    `json:"name"`
+	State    string    `json:"state"`
+	Failures int       `json:"consecutiveFailures"`
+	OpenedAt time.Time `json:"openedAt,omitzero"`
+	Stats    Stats     `json:"-"`
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []*Breaker
+)
+
+// New 创建熔断器并登记到全局列表，供健康检查与监控指标读取
+// cfg.Enabled 为 false 时返回 nil，nil 熔断器放行所有调用
+func New(name string, cfg config.CircuitBreakerConfig) *Breaker {
+	if !cfg.Enabled {
+		return nil
+	}
+	b := &Breaker{name: name, cfg: cfg, now: time.Now}
+	registryMu.Lock()
+	registry = append(registry, b)
+	registryMu.Unlock()
+	return b
+}
+
+// All 返回全部熔断器的状态，按创建顺序排列
+func All() []Snapshot {
+	registryMu.Lock()
+	breakers := append([]*Breaker(nil), registry...)
+	registryMu.Unlock()
+	snapshots := make([]Snapshot, 0, len(breakers))
+	for _, b := range breakers {
+		snapshots = append(snapshots, b.Snapshot())
+	}
+	return snapshots
+}
+
+// Name 返回熔断器名称
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow 判断是否放行一次调用，熔断期间返回 *OpenError
+// 放行后必须以调用结果调用一次返回的 Call 的 Done 或 Release
+func (b *Breaker) Allow() (Call, error) {
+	if b == nil {
+		return Call{}, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		wait := b.openedAt.Add(b.cfg.OpenTimeout).Sub(b.now())
+		if wait > 0 {
+			b.stats.Rejected++
+			return Call{}, &OpenError{Name: b.name, RetryAfter: wait}
+		}
+		b.state = StateHalfOpen
+		b.probes = 0
+		b.halfOpen++
+	}
+	call := Call{b: b}
+	if b.state == StateHalfOpen {
+		if b.probes >= b.cfg.HalfOpenMaxCalls {
+			b.stats.Rejected++
+			return Call{}, &OpenError{Name: b.name, RetryAfter: time.Second}
+		}
+		b.probes++
+		call.probe, call.round = true, b.halfOpen
+	}
+	b.stats.Calls++
+	return call, nil
+}
+
+// Done 记录一次已放行调用的结果；failed 表示调用失败，elapsed 为调用耗时
+// 只有本轮的探测调用决定半开状态的去留；熔断前放行、熔断后才结束的调用只计入统计
+func (c Call) Done(failed bool, elapsed time.Duration) {
+	b := c.b
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	probe := c.endProbeLocked()
+	if b.cfg.SlowCallThreshold > 0 && elapsed > b.cfg.SlowCallThreshold {
+		b.stats.SlowCalls++
+		failed = true
+	}
+	if failed {
+		b.stats.Failures++
+	}
+
+	switch {
+	case probe && !failed:
+		b.state = StateClosed
+		b.failures = 0
+	case probe:
+		// 探测失败立即重新熔断
+		b.failures++
+		b.open()
+	case b.state == StateClosed && !failed:
+		b.failures = 0
+	case b.state == StateClosed:
+		b.failures++
+		if b.failures >= b.cfg.ConsecutiveFailures {
+			b.open()
+		}
+	}
+}
+
+// Release 结束一次已放行、但结果不说明依赖状态的调用（例如调用方取消或超时），不计入成功或失败
+func (c Call) Release() {
+	b := c.b
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c.endProbeLocked()
+}
+
+// endProbeLocked 归还探测名额，返回该调用是否为当前这一轮的探测调用
+func (c Call) endProbeLocked() bool {
+	b := c.b
+	if !c.probe || c.round != b.halfOpen || b.state != StateHalfOpen {
+		return false
+	}
+	if b.probes > 0 {
+		b.probes--
+	}
+	return true
+}
+
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+	b.stats.Opened++
+}
+
+// Snapshot 返回熔断器的当前状态
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := Snapshot{Name: b.name, State: b.state.String(), Failures: b.failures, Stats: b.stats}
+	if b.state != StateClosed {
+		s.OpenedAt = b.openedAt
+	}
+	return s
+}
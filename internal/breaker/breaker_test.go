@@ -0,0 +1,162 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"mcp/config"
+)
+
+// newTestBreaker 创建使用可控时钟的熔断器，不登记到全局列表
+func newTestBreaker(now *time.Time) *Breaker {
+	return &Breaker{
+		name: "test",
+		cfg: config.CircuitBreakerConfig{
+			Enabled:             true,
+			ConsecutiveFailures: 2,
+			OpenTimeout:         time.Minute,
+			HalfOpenMaxCalls:    1,
+		},
+		now: func() time.Time { return *now },
+	}
+}
+
+func mustAllow(t *testing.T, b *Breaker) Call {
+	t.Helper()
+	call, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	return call
+}
+
+func wantState(t *testing.T, b *Breaker, want State) {
+	t.Helper()
+	if got := b.Snapshot().State; got != want.String() {
+		t.Fatalf("state = %s, want %s", got, want)
+	}
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	mustAllow(t, b).Done(true, 0)
+	wantState(t, b, StateClosed)
+	mustAllow(t, b).Done(true, 0)
+	wantState(t, b, StateOpen)
+
+	if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Allow while open: err = %v, want ErrOpen", err)
+	}
+
+	now = now.Add(time.Minute)
+	probe := mustAllow(t, b)
+	wantState(t, b, StateHalfOpen)
+	if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Allow beyond half_open_max_calls: err = %v, want ErrOpen", err)
+	}
+	probe.Done(false, 0)
+	wantState(t, b, StateClosed)
+}
+
+func TestBreakerProbeFailureReopens(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+	mustAllow(t, b).Done(true, 0)
+	mustAllow(t, b).Done(true, 0)
+
+	now = now.Add(time.Minute)
+	mustAllow(t, b).Done(true, 0)
+	wantState(t, b, StateOpen)
+}
+
+func TestBreakerIgnoresCallsAdmittedWhileClosed(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+
+	// 熔断前放行、半开后才结束的调用
+	late := mustAllow(t, b)
+	mustAllow(t, b).Done(true, 0)
+	mustAllow(t, b).Done(true, 0)
+	wantState(t, b, StateOpen)
+
+	now = now.Add(time.Minute)
+	probe := mustAllow(t, b)
+
+	// 迟到的结果既不占用也不归还探测名额，也不改变半开状态
+	late.Done(false, 0)
+	wantState(t, b, StateHalfOpen)
+	if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Allow after late result: err = %v, want ErrOpen (probe still in flight)", err)
+	}
+
+	probe.Done(false, 0)
+	wantState(t, b, StateClosed)
+}
+
+func TestBreakerIgnoresProbesFromEarlierRound(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+	mustAllow(t, b).Done(true, 0)
+	mustAllow(t, b).Done(true, 0)
+
+	// 第一轮的探测调用迟迟没有结束，期间熔断器又经历了一次熔断
+	now = now.Add(time.Minute)
+	stale := mustAllow(t, b)
+	b.mu.Lock()
+	b.open()
+	b.mu.Unlock()
+
+	now = now.Add(time.Minute)
+	probe := mustAllow(t, b)
+	stale.Done(false, 0)
+	wantState(t, b, StateHalfOpen)
+	if _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Allow after stale probe: err = %v, want ErrOpen", err)
+	}
+	probe.Done(false, 0)
+	wantState(t, b, StateClosed)
+}
+
+func TestBreakerRelease(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+	mustAllow(t, b).Done(true, 0)
+
+	// Release 不重置连续失败次数
+	mustAllow(t, b).Release()
+	mustAllow(t, b).Done(true, 0)
+	wantState(t, b, StateOpen)
+
+	// 释放的探测名额可以再次使用
+	now = now.Add(time.Minute)
+	mustAllow(t, b).Release()
+	wantState(t, b, StateHalfOpen)
+	mustAllow(t, b).Done(false, 0)
+	wantState(t, b, StateClosed)
+}
+
+func TestBreakerSlowCallsCountAsFailures(t *testing.T) {
+	now := time.Now()
+	b := newTestBreaker(&now)
+	b.cfg.SlowCallThreshold = time.Second
+
+	mustAllow(t, b).Done(false, 2*time.Second)
+	mustAllow(t, b).Done(false, 2*time.Second)
+	wantState(t, b, StateOpen)
+	if s := b.Snapshot().Stats; s.SlowCalls != 2 || s.Failures != 2 || s.Opened != 1 {
+		t.Fatalf("stats = %+v", s)
+	}
+}
+
+func TestNilBreakerAllowsEverything(t *testing.T) {
+	var b *Breaker
+	call, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	call.Done(true, time.Hour)
+	call.Release()
+}
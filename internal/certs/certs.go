@@ -0,0 +1,138 @@
+// Package certs 加载 TLS 证书与 CA，并在文件变化后自动重新加载
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"mcp/pkg/log"
+)
+
+// LoadCertPool 从 PEM 文件加载 CA 证书池
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 CA 文件 %s: %w", file, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA 文件 %s 中没有合法的 PEM 证书", file)
+	}
+	return pool, nil
+}
+
+// Reloader 持有服务端证书与校验客户端证书的 CA，
+// 每次 TLS 握手前检查文件的修改时间，变化后重新加载，加载失败则继续使用旧的内容
+type Reloader struct {
+	certFile, keyFile, caFile string
+
+	mu       sync.RWMutex
+	modTimes [3]time.Time
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+}
+
+// NewReloader 加载证书与私钥；caFile 为空时不校验客户端证书
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.reload(r.stat()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader) stat() [3]time.Time {
+	var mt [3]time.Time
+	for i, f := range []string{r.certFile, r.keyFile, r.caFile} {
+		if f == "" {
+			continue
+		}
+		if info, err := os.Stat(f); err == nil {
+			mt[i] = info.ModTime()
+		}
+	}
+	return mt
+}
+
+func (r *Reloader) reload(modTimes [3]time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("无法加载 TLS 证书 %s: %w", r.certFile, err)
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		if pool, err = LoadCertPool(r.caFile); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCA = pool
+	r.modTimes = modTimes
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	mt := r.stat()
+	r.mu.RLock()
+	changed := mt != r.modTimes
+	r.mu.RUnlock()
+	if changed {
+		if err := r.reload(mt); err != nil {
+			log.Warn("重新加载 TLS 证书失败", "cert", r.certFile, "error", err)
+		} else {
+			log.Info("已重新加载 TLS 证书", "cert", r.certFile)
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, r.clientCA
+}
+
+// ServerConfig 返回 HTTPS 服务端使用的 tls.Config，每次握手使用最新的证书与客户端 CA
+func (r *Reloader) ServerConfig(clientAuth tls.ClientAuthType) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    pool,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+}
+
+// ClientConfig 返回连接 TLS 服务端使用的 tls.Config
+// caFile 为空时使用系统根证书；certFile 与 keyFile 不为空时出示客户端证书 (mTLS)
+func ClientConfig(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+	if caFile != "" {
+		pool, err := LoadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("无法加载客户端证书 %s: %w", certFile, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
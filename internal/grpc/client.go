@@ -2,42 +2,166 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/health" // 注册客户端健康检查
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 
+	"mcp/config"
+	"mcp/internal/breaker"
 	pb "mcp/proto"
 )
 
-var client pb.McpExtensionServiceClient
+// backends 是全部后端集群，由 InitClient 创建
+var backends *backendSet
 
-// InitClient 连接到 Java gRPC 服务端
-func InitClient(target string) (*grpc.ClientConn, error) {
-	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// ErrNotInitialized 表示后端连接尚未建立（未调用 InitClient 或初始化失败）
+var ErrNotInitialized = errors.New("gRPC 客户端尚未初始化")
+
+// ErrUnavailable 表示后端暂时不可用，调用已按重试策略重试仍然失败
+var ErrUnavailable = errors.New("后端服务暂不可用，请稍后重试")
+
+// InitClient 为每个后端集群的每个副本创建到 Java gRPC 服务端的连接
+// 连接是惰性建立的，后端暂不可用不会导致启动失败，断开后按退避策略自动重连；
+// 每次调用都会通过拦截器携带调用方身份、请求 ID 与链路追踪信息，见 metadata.go；
+// 调用按 grpc.routes 路由到集群，在集群的副本间负载均衡并摘除异常副本，见 cluster.go
+func InitClient(cfg config.GrpcConfig) error {
+	opts, err := credentialOptions(cfg)
 	if err != nil {
-		log.Printf("无法连接到 gRPC 服务端 %s: %v", target, err)
-		return nil, err
+		log.Printf("无法初始化 gRPC 连接凭据: %v", err)
+		return err
+	}
+	opts = append(opts,
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  time.Second,
+				Multiplier: 1.6,
+				Jitter:     0.2,
+				MaxDelay:   cfg.ReconnectMaxBackoff,
+			},
+			MinConnectTimeout: 5 * time.Second,
+		}),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.Keepalive.Time,
+			Timeout:             cfg.Keepalive.Timeout,
+			PermitWithoutStream: cfg.Keepalive.PermitWithoutStream,
+		}),
+		grpc.WithChainStreamInterceptor(StreamMetadataInterceptor()),
+	)
+	set, err := newBackendSet(cfg, func(c *cluster, ep *endpoint) (*grpc.ClientConn, error) {
+		conn, err := grpc.NewClient(ep.target, append(opts,
+			grpc.WithDefaultServiceConfig(serviceConfig(cfg, c.policy)),
+			// 熔断与摘除统计位于元数据拦截器之外，记录的是重试之后的最终结果
+			grpc.WithChainUnaryInterceptor(c.breakerInterceptor(), c.outlierInterceptor(ep), UnaryMetadataInterceptor()),
+		)...)
+		if err != nil {
+			log.Printf("无法连接到 gRPC 服务端 %s: %v", ep.target, err)
+			return nil, err
+		}
+		// 立即开始建立连接，使第一次调用无需等待
+		conn.Connect()
+		log.Printf("成功初始化 gRPC 连接，集群: %s，目标地址: %s，TLS: %t", c.name, ep.target, cfg.TLS.Enabled)
+		return conn, nil
+	})
+	if err != nil {
+		return err
+	}
+	backends = set
+	return nil
+}
+
+// serviceConfig 返回连接的默认 service config：按 policy 在地址解析出的多个地址间负载均衡，
+// 对 UNAVAILABLE 重试，并开启客户端健康检查；后端未实现 grpc.health.v1 时健康检查自动失效，连接视为健康
+func serviceConfig(cfg config.GrpcConfig, policy string) string {
+	sc := map[string]any{
+		"loadBalancingConfig": []any{map[string]any{policy: map[string]any{}}},
+	}
+	if r := cfg.Retry; r.MaxAttempts > 1 {
+		sc["methodConfig"] = []any{map[string]any{
+			"name": []any{map[string]any{}},
+			"retryPolicy": map[string]any{
+				"maxAttempts":          r.MaxAttempts,
+				"initialBackoff":       durationJSON(r.InitialBackoff),
+				"maxBackoff":           durationJSON(r.MaxBackoff),
+				"backoffMultiplier":    r.BackoffMultiplier,
+				"retryableStatusCodes": []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+	if cfg.HealthCheck.Enabled {
+		sc["healthCheckConfig"] = map[string]any{"serviceName": cfg.HealthCheck.ServiceName}
+	}
+	b, _ := json.Marshal(sc)
+	return string(b)
+}
+
+// durationJSON 按 service config 的要求将时长格式化为以秒为单位的字符串，如 "0.1s"
+func durationJSON(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// Close 关闭与后端的全部连接，在服务退出时调用
+func Close() error {
+	if backends == nil {
+		return nil
 	}
-	client = pb.NewMcpExtensionServiceClient(conn)
-	log.Printf("成功初始化 gRPC 连接，目标地址: %s", target)
-	return conn, nil
+	return backends.close()
 }
 
-// GetClient 返回已经初始化好的 gRPC 客户端实例
-func GetClient() pb.McpExtensionServiceClient {
-	if client == nil {
-		log.Fatal("gRPC 客户端尚未初始化，请先调用 InitClient。")
+// clientFor 返回处理 fullMethod 调用的副本客户端
+func clientFor(ctx context.Context, fullMethod string) (pb.McpExtensionServiceClient, error) {
+	if backends == nil {
+		return nil, ErrNotInitialized
 	}
-	return client
+	return backends.route(ctx, fullMethod).pick().client, nil
+}
+
+// connFor 返回处理 fullMethod 调用的副本连接，供动态调用使用
+func connFor(ctx context.Context, fullMethod string) (*grpc.ClientConn, error) {
+	if backends == nil {
+		return nil, ErrNotInitialized
+	}
+	return backends.route(ctx, fullMethod).pick().conn, nil
+}
+
+// GetConn 返回默认集群中一个副本的连接，供反射等不按方法路由的调用使用
+func GetConn() (*grpc.ClientConn, error) {
+	if backends == nil {
+		return nil, ErrNotInitialized
+	}
+	return backends.clusters[DefaultCluster].pick().conn, nil
+}
+
+// rpcError 包装调用错误，后端不可用时返回 ErrUnavailable，便于工具向客户端返回明确的提示
+// 熔断期间返回的 *breaker.OpenError 原样保留，其中包含建议的重试时间
+func rpcError(method string, err error) error {
+	if errors.Is(err, breaker.ErrOpen) {
+		return err
+	}
+	if status.Code(err) == codes.Unavailable {
+		return fmt.Errorf("gRPC %s failed: %w", method, ErrUnavailable)
+	}
+	return fmt.Errorf("gRPC %s failed: %w", method, err)
 }
 
 // SearchDiary 调用后端的 SearchDiary RPC 接口
 func SearchDiary(ctx context.Context, request *pb.SearchDiaryRequest) (*pb.SearchDiaryResponse, error) {
-	res, err := GetClient().SearchDiary(ctx, request)
+	c, err := clientFor(ctx, pb.McpExtensionService_SearchDiary_FullMethodName)
 	if err != nil {
-		return nil, fmt.Errorf("gRPC SearchDiary failed: %w", err)
+		return nil, err
+	}
+	res, err := c.SearchDiary(ctx, request)
+	if err != nil {
+		return nil, rpcError("SearchDiary", err)
 	}
 	if res.ErrorMessage != "" {
 		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
@@ -47,9 +171,141 @@ func SearchDiary(ctx context.Context, request *pb.SearchDiaryRequest) (*pb.Searc
 
 // SearchMemory 调用后端的 SearchMemory RPC 接口
 func SearchMemory(ctx context.Context, request *pb.SearchMemoryRequest) (*pb.SearchMemoryResponse, error) {
-	res, err := GetClient().SearchMemory(ctx, request)
+	c, err := clientFor(ctx, pb.McpExtensionService_SearchMemory_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.SearchMemory(ctx, request)
+	if err != nil {
+		return nil, rpcError("SearchMemory", err)
+	}
+	if res.ErrorMessage != "" {
+		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
+	}
+	return res, nil
+}
+
+// SearchLifeGraph 调用后端的 SearchLifeGraph RPC 接口
+func SearchLifeGraph(ctx context.Context, request *pb.SearchLifeGraphRequest) (*pb.SearchLifeGraphResponse, error) {
+	c, err := clientFor(ctx, pb.McpExtensionService_SearchLifeGraph_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.SearchLifeGraph(ctx, request)
+	if err != nil {
+		return nil, rpcError("SearchLifeGraph", err)
+	}
+	if res.ErrorMessage != "" {
+		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
+	}
+	return res, nil
+}
+
+// GetDiary 调用后端的 GetDiary RPC 接口，按 ID 获取单篇日记
+func GetDiary(ctx context.Context, request *pb.GetDiaryRequest) (*pb.GetDiaryResponse, error) {
+	c, err := clientFor(ctx, pb.McpExtensionService_GetDiary_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.GetDiary(ctx, request)
+	if err != nil {
+		return nil, rpcError("GetDiary", err)
+	}
+	if res.ErrorMessage != "" {
+		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
+	}
+	return res, nil
+}
+
+// ListDiaries 调用后端的 ListDiaries RPC 接口，列出用户最近的日记
+func ListDiaries(ctx context.Context, request *pb.ListDiariesRequest) (*pb.ListDiariesResponse, error) {
+	c, err := clientFor(ctx, pb.McpExtensionService_ListDiaries_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.ListDiaries(ctx, request)
+	if err != nil {
+		return nil, rpcError("ListDiaries", err)
+	}
+	if res.ErrorMessage != "" {
+		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
+	}
+	return res, nil
+}
+
+// GetMemory 调用后端的 GetMemory RPC 接口，按来源 ID 获取单条记忆
+func GetMemory(ctx context.Context, request *pb.GetMemoryRequest) (*pb.GetMemoryResponse, error) {
+	c, err := clientFor(ctx, pb.McpExtensionService_GetMemory_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.GetMemory(ctx, request)
+	if err != nil {
+		return nil, rpcError("GetMemory", err)
+	}
+	if res.ErrorMessage != "" {
+		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
+	}
+	return res, nil
+}
+
+// ListMemories 调用后端的 ListMemories RPC 接口，列出用户最近的记忆
+func ListMemories(ctx context.Context, request *pb.ListMemoriesRequest) (*pb.ListMemoriesResponse, error) {
+	c, err := clientFor(ctx, pb.McpExtensionService_ListMemories_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.ListMemories(ctx, request)
+	if err != nil {
+		return nil, rpcError("ListMemories", err)
+	}
+	if res.ErrorMessage != "" {
+		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
+	}
+	return res, nil
+}
+
+// ValidateApiKey 调用后端的 ValidateApiKey RPC 接口，校验 API Key 并解析其所属用户
+func ValidateApiKey(ctx context.Context, request *pb.ValidateApiKeyRequest) (*pb.ValidateApiKeyResponse, error) {
+	c, err := clientFor(ctx, pb.McpExtensionService_ValidateApiKey_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.ValidateApiKey(ctx, request)
+	if err != nil {
+		return nil, rpcError("ValidateApiKey", err)
+	}
+	if res.ErrorMessage != "" {
+		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
+	}
+	return res, nil
+}
+
+// ListTools 调用后端的 ListTools RPC 接口，获取后端声明的工具列表
+func ListTools(ctx context.Context, request *pb.ListToolsRequest) (*pb.ListToolsResponse, error) {
+	c, err := clientFor(ctx, pb.McpExtensionService_ListTools_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.ListTools(ctx, request)
+	if err != nil {
+		return nil, rpcError("ListTools", err)
+	}
+	if res.ErrorMessage != "" {
+		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
+	}
+	return res, nil
+}
+
+// CallTool 调用后端的 CallTool RPC 接口，执行后端声明的工具
+func CallTool(ctx context.Context, request *pb.CallToolRequest) (*pb.CallToolResponse, error) {
+	c, err := clientFor(ctx, pb.McpExtensionService_CallTool_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.CallTool(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("gRPC SearchMemory failed: %w", err)
+		return nil, rpcError("CallTool", err)
 	}
 	if res.ErrorMessage != "" {
 		return nil, fmt.Errorf("后端返回错误：%s", res.ErrorMessage)
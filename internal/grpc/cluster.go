@@ -0,0 +1,270 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"mcp/config"
+	"mcp/internal/breaker"
+	"mcp/internal/reqctx"
+	pb "mcp/proto"
+)
+
+// DefaultCluster 是由 grpc.backend_target / grpc.targets 组成的默认集群名称
+const DefaultCluster = "default"
+
+// endpoint 是集群中的一个副本，每个副本使用独立的连接
+// 副本地址为 DNS 名称时，连接内部仍按 load_balancing 在解析出的地址间分配调用
+type endpoint struct {
+	target string
+	conn   *grpc.ClientConn
+	client pb.McpExtensionServiceClient
+
+	// 以下字段由 cluster.mu 保护
+	failures     int
+	ejections    int
+	ejectedUntil time.Time
+}
+
+// cluster 是一组提供相同服务的副本
+// 副本之间的负载均衡与异常副本摘除在应用层完成：grpc-go 的 outlier detection 仅对 xDS 开放
+type cluster struct {
+	name      string
+	policy    string
+	outlier   config.OutlierDetectionConfig
+	endpoints []*endpoint
+	next      atomic.Uint64
+	breaker   *breaker.Breaker
+
+	mu sync.Mutex
+}
+
+// backendSet 是全部后端集群及其路由规则
+type backendSet struct {
+	clusters     map[string]*cluster
+	names        []string          // 集群按配置顺序排列，默认集群在最前
+	toolRoutes   map[string]string // 工具名称 -> 集群
+	methodRoutes map[string]string // "/pkg.Service/Method" -> 集群
+}
+
+// clusterConfigs 返回包括默认集群在内的全部集群配置
+func clusterConfigs(cfg config.GrpcConfig) []config.BackendClusterConfig {
+	targets := cfg.Targets
+	if len(targets) == 0 {
+		targets = []string{cfg.BackendTarget}
+	}
+	return append([]config.BackendClusterConfig{{
+		Name:          DefaultCluster,
+		Targets:       targets,
+		LoadBalancing: cfg.LoadBalancing,
+	}}, cfg.Clusters...)
+}
+
+// newBackendSet 为每个集群的每个副本建立连接，dial 根据副本所属集群与地址创建连接
+func newBackendSet(cfg config.GrpcConfig, dial func(c *cluster, ep *endpoint) (*grpc.ClientConn, error)) (*backendSet, error) {
+	set := &backendSet{
+		clusters:     make(map[string]*cluster),
+		toolRoutes:   make(map[string]string),
+		methodRoutes: make(map[string]string),
+	}
+	for _, cc := range clusterConfigs(cfg) {
+		c := &cluster{
+			name:    cc.Name,
+			policy:  cc.LoadBalancing,
+			outlier: cfg.OutlierDetection,
+			breaker: breaker.New("backend/"+cc.Name, cfg.CircuitBreaker),
+		}
+		for _, target := range cc.Targets {
+			ep := &endpoint{target: target}
+			conn, err := dial(c, ep)
+			if err != nil {
+				set.close()
+				return nil, err
+			}
+			ep.conn = conn
+			ep.client = pb.NewMcpExtensionServiceClient(conn)
+			c.endpoints = append(c.endpoints, ep)
+		}
+		set.clusters[c.name] = c
+		set.names = append(set.names, c.name)
+	}
+	for _, r := range cfg.Routes {
+		if r.Tool != "" {
+			set.toolRoutes[r.Tool] = r.Cluster
+			continue
+		}
+		set.methodRoutes["/"+strings.TrimPrefix(r.Method, "/")] = r.Cluster
+	}
+	return set, nil
+}
+
+// route 选择处理调用的集群：先按当前工具匹配，再按 RPC 方法匹配，都未命中时使用默认集群
+func (s *backendSet) route(ctx context.Context, fullMethod string) *cluster {
+	if name, ok := s.toolRoutes[reqctx.From(ctx).Tool]; ok {
+		return s.clusters[name]
+	}
+	if name, ok := s.methodRoutes[fullMethod]; ok {
+		return s.clusters[name]
+	}
+	return s.clusters[DefaultCluster]
+}
+
+func (s *backendSet) close() error {
+	var errs []error
+	for _, name := range s.names {
+		for _, ep := range s.clusters[name].endpoints {
+			if ep.conn != nil {
+				errs = append(errs, ep.conn.Close())
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pick 按负载均衡策略选择一个未被摘除的副本；全部被摘除时退回到全部副本
+func (c *cluster) pick() *endpoint {
+	now := time.Now()
+	c.mu.Lock()
+	candidates := make([]*endpoint, 0, len(c.endpoints))
+	for _, ep := range c.endpoints {
+		if !ep.ejectedUntil.After(now) {
+			candidates = append(candidates, ep)
+		}
+	}
+	c.mu.Unlock()
+	if len(candidates) == 0 {
+		candidates = c.endpoints
+	}
+
+	if c.policy == "pick_first" {
+		// 依次使用第一个连接正常的副本，前面的副本恢复后自动切回
+		for _, ep := range candidates {
+			if s := ep.conn.GetState(); s != connectivity.TransientFailure && s != connectivity.Shutdown {
+				return ep
+			}
+		}
+		return candidates[0]
+	}
+	return candidates[c.next.Add(1)%uint64(len(candidates))]
+}
+
+// breakerInterceptor 在集群熔断期间直接拒绝调用，并按调用结果与耗时更新熔断器，只有传输层错误计为失败
+func (c *cluster) breakerInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if method == healthpb.Health_Check_FullMethodName {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		call, err := c.breaker.Allow()
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		// 调用方取消或到达调用方的截止时间不说明集群有问题，既不计为失败也不计为成功
+		if ctx.Err() != nil {
+			call.Release()
+			return err
+		}
+		call.Done(isTransportFailure(err), time.Since(start))
+		return err
+	}
+}
+
+// outlierInterceptor 记录副本每次调用的结果，用于异常副本摘除
+func (c *cluster) outlierInterceptor(ep *endpoint) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		// 健康检查由 /health 发起，结果不计入摘除统计
+		if method != healthpb.Health_Check_FullMethodName {
+			c.record(ep, err)
+		}
+		return err
+	}
+}
+
+// isTransportFailure 判断错误是否说明集群无法访问或没有及时响应，只有这类错误计入熔断
+// Internal、Unknown 等可能由个别请求触发的异常不应使整个集群熔断
+func isTransportFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// isEndpointFailure 判断错误是否说明副本本身有问题，业务错误与调用方取消不计入
+func isEndpointFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
+}
+
+func (c *cluster) record(ep *endpoint, err error) {
+	// 只有一个副本时摘除没有意义
+	if !c.outlier.Enabled || len(c.endpoints) < 2 || status.Code(err) == codes.Canceled {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if !isEndpointFailure(err) {
+		ep.failures = 0
+		// 恢复后的副本持续正常时，下次摘除重新从基础时长开始计算
+		if !ep.ejectedUntil.IsZero() && !ep.ejectedUntil.After(now) {
+			ep.ejectedUntil = time.Time{}
+			ep.ejections = 0
+		}
+		return
+	}
+	ep.failures++
+	if ep.failures < c.outlier.ConsecutiveFailures || ep.ejectedUntil.After(now) || !c.canEjectLocked(now) {
+		return
+	}
+	ep.failures = 0
+	ep.ejections++
+	d := c.outlier.BaseEjectionTime * time.Duration(ep.ejections)
+	if c.outlier.MaxEjectionTime > 0 && d > c.outlier.MaxEjectionTime {
+		d = c.outlier.MaxEjectionTime
+	}
+	ep.ejectedUntil = now.Add(d)
+	log.Printf("后端副本 %s（集群 %s）连续调用失败，摘除 %s: %v", ep.target, c.name, d, err)
+}
+
+// canEjectLocked 判断摘除一个副本后是否仍满足 max_ejection_percent，且至少保留一个副本
+func (c *cluster) canEjectLocked(now time.Time) bool {
+	ejected := 0
+	for _, ep := range c.endpoints {
+		if ep.ejectedUntil.After(now) {
+			ejected++
+		}
+	}
+	limit := len(c.endpoints) * c.outlier.MaxEjectionPercent / 100
+	if limit >= len(c.endpoints) {
+		limit = len(c.endpoints) - 1
+	}
+	return ejected < limit
+}
+
+// ejected 返回副本的摘除截止时间，未被摘除时返回零值
+func (c *cluster) ejected(ep *endpoint) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ep.ejectedUntil.After(time.Now()) {
+		return ep.ejectedUntil
+	}
+	return time.Time{}
+}
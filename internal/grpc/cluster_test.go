@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"mcp/config"
+	"mcp/internal/breaker"
+)
+
+const testMethod = "/mcp.extension.McpExtensionService/SearchDiary"
+
+func newBreakerCluster(name string) *cluster {
+	return &cluster{
+		name: name,
+		breaker: breaker.New("backend/"+name, config.CircuitBreakerConfig{
+			Enabled:             true,
+			ConsecutiveFailures: 1,
+			OpenTimeout:         10 * time.Millisecond,
+			HalfOpenMaxCalls:    1,
+		}),
+	}
+}
+
+// invokerReturning 返回在 ctx 结束时返回 ctx 对应状态码、否则返回 err 的 invoker
+func invokerReturning(err error) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return err
+	}
+}
+
+func TestBreakerInterceptorCountsTransportFailures(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		open bool
+	}{
+		{codes.Unavailable, true},
+		{codes.DeadlineExceeded, true},
+		{codes.Internal, false},
+		{codes.Unknown, false},
+		{codes.NotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			c := newBreakerCluster("codes-" + tt.code.String())
+			c.breakerInterceptor()(context.Background(), testMethod, nil, nil, nil, invokerReturning(status.Error(tt.code, "x")))
+			if got := c.breaker.Snapshot().State == "open"; got != tt.open {
+				t.Fatalf("open = %v, want %v", got, tt.open)
+			}
+		})
+	}
+}
+
+func TestBreakerInterceptorIgnoresCallerDeadline(t *testing.T) {
+	c := newBreakerCluster("caller-deadline")
+	interceptor := c.breakerInterceptor()
+
+	// 调用方自身的截止时间到达，后端返回 DeadlineExceeded，不计为集群失败
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if err := interceptor(ctx, testMethod, nil, nil, nil, invokerReturning(nil)); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if s := c.breaker.Snapshot(); s.State != "closed" || s.Failures != 0 {
+		t.Fatalf("after caller deadline: state %s, failures %d; want closed, 0", s.State, s.Failures)
+	}
+}
+
+func TestBreakerInterceptorCancelledProbeKeepsBreakerHalfOpen(t *testing.T) {
+	c := newBreakerCluster("cancelled-probe")
+	interceptor := c.breakerInterceptor()
+
+	interceptor(context.Background(), testMethod, nil, nil, nil, invokerReturning(status.Error(codes.Unavailable, "down")))
+	if s := c.breaker.Snapshot(); s.State != "open" {
+		t.Fatalf("state = %s, want open", s.State)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// 被调用方取消的探测调用既不关闭熔断器，也不占用探测名额
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := interceptor(ctx, testMethod, nil, nil, nil, invokerReturning(nil)); status.Code(err) != codes.Canceled {
+		t.Fatalf("err = %v, want Canceled", err)
+	}
+	if s := c.breaker.Snapshot(); s.State != "half_open" {
+		t.Fatalf("after cancelled probe: state %s, want half_open", s.State)
+	}
+
+	if err := interceptor(context.Background(), testMethod, nil, nil, nil, invokerReturning(nil)); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if s := c.breaker.Snapshot(); s.State != "closed" {
+		t.Fatalf("after successful probe: state %s, want closed", s.State)
+	}
+}
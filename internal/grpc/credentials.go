@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"mcp/config"
+	"mcp/internal/certs"
+)
+
+// tokenCredentials 随每次调用发送服务令牌
+type tokenCredentials struct {
+	header     string
+	value      string
+	requireTLS bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	if strings.EqualFold(t.header, "authorization") {
+		return map[string]string{"authorization": "Bearer " + t.value}, nil
+	}
+	return map[string]string{strings.ToLower(t.header): t.value}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return t.requireTLS
+}
+
+// credentialOptions 根据配置返回连接的传输凭据与 per-RPC 凭据
+func credentialOptions(cfg config.GrpcConfig) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if t := cfg.TLS; t.Enabled {
+		tlsCfg, err := certs.ClientConfig(t.CAFile, t.CertFile, t.KeyFile, t.ServerName)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if t := cfg.Token; t.Value != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{
+			header:     t.Header,
+			value:      t.Value,
+			requireTLS: !t.AllowInsecure,
+		}))
+	}
+	return opts, nil
+}
@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mcp/internal/reqctx"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// FindMethod 查找后端 gRPC 方法的描述符
+// 优先通过 gRPC server reflection 从后端获取，使声明式工具无需重新编译即可绑定新方法；
+// 后端未开启反射或暂不可用时，退回到本服务编译时链接的 proto 描述符。
+// fullMethod 支持 "pkg.Service/Method"、"/pkg.Service/Method" 与 "pkg.Service.Method" 三种写法
+func FindMethod(ctx context.Context, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	var svcName, methodName string
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		svcName, methodName = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, "."); i >= 0 {
+		svcName, methodName = name[:i], name[i+1:]
+	} else {
+		return nil, fmt.Errorf("非法的 gRPC 方法名: %s", fullMethod)
+	}
+
+	md, err := reflectMethod(ctx, svcName, methodName)
+	if err == nil {
+		return md, nil
+	}
+	local, localErr := findMethodIn(protoregistry.GlobalFiles, svcName, methodName)
+	if localErr != nil {
+		return nil, fmt.Errorf("%w（本地描述符: %v）", err, localErr)
+	}
+	return local, nil
+}
+
+// reflectMethod 通过 gRPC server reflection 获取后端方法的描述符
+func reflectMethod(ctx context.Context, svcName, methodName string) (protoreflect.MethodDescriptor, error) {
+	rc, err := newReflectionClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.close()
+
+	if err := rc.fetchSymbol(svcName); err != nil {
+		return nil, err
+	}
+	files, err := rc.files()
+	if err != nil {
+		return nil, fmt.Errorf("无法构建后端描述符: %w", err)
+	}
+	return findMethodIn(files, svcName, methodName)
+}
+
+// findMethodIn 在描述符注册表中查找服务的方法
+func findMethodIn(files *protoregistry.Files, svcName, methodName string) (protoreflect.MethodDescriptor, error) {
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(svcName))
+	if err != nil {
+		return nil, fmt.Errorf("未找到 gRPC 服务 %s: %w", svcName, err)
+	}
+	svc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s 不是 gRPC 服务", svcName)
+	}
+	md := svc.Methods().ByName(protoreflect.Name(methodName))
+	if md == nil {
+		return nil, fmt.Errorf("服务 %s 中未找到方法 %s", svcName, methodName)
+	}
+	return md, nil
+}
+
+// InvokeJSON 以 JSON 作为入参和出参调用后端的 unary 方法，无需生成的客户端桩代码
+// 若响应消息包含非空的 error_message 字段，则作为错误返回
+func InvokeJSON(ctx context.Context, md protoreflect.MethodDescriptor, reqJSON []byte) ([]byte, error) {
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, fmt.Errorf("不支持流式方法 %s", md.FullName())
+	}
+
+	in, err := requestMessage(ctx, md.Input(), reqJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	out := dynamicpb.NewMessage(md.Output())
+	method := fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name())
+	cc, err := connFor(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	if err := cc.Invoke(ctx, method, in, out); err != nil {
+		return nil, rpcError(string(md.Name()), err)
+	}
+	if fd := md.Output().Fields().ByName("error_message"); fd != nil && fd.Kind() == protoreflect.StringKind {
+		if msg := out.Get(fd).String(); msg != "" {
+			return nil, fmt.Errorf("后端返回错误：%s", msg)
+		}
+	}
+
+	return protojson.Marshal(out)
+}
+
+// requestMessage 将 JSON 参数转换为请求消息
+// 若请求消息包含 api_key 字段，总是以 reqctx.BackendAPIKey 覆盖，调用方已通过认证或没有 API Key 时清空，
+// 不会把客户端在参数中填写的值转发给后端
+func requestMessage(ctx context.Context, md protoreflect.MessageDescriptor, reqJSON []byte) (*dynamicpb.Message, error) {
+	in := dynamicpb.NewMessage(md)
+	if len(reqJSON) > 0 {
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(reqJSON, in); err != nil {
+			return nil, fmt.Errorf("无法将参数转换为 %s: %w", md.FullName(), err)
+		}
+	}
+	if fd := md.Fields().ByName("api_key"); fd != nil && fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+		if apiKey := reqctx.BackendAPIKey(ctx); apiKey != "" {
+			in.Set(fd, protoreflect.ValueOfString(apiKey))
+		} else {
+			in.Clear(fd)
+		}
+	}
+	return in, nil
+}
@@ -0,0 +1,166 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"mcp/config"
+	"mcp/internal/reqctx"
+	pb "mcp/proto"
+)
+
+func TestRequestMessageOverridesAPIKey(t *testing.T) {
+	md := (&pb.SearchDiaryRequest{}).ProtoReflect().Descriptor()
+	apiKey := md.Fields().ByName("api_key")
+	keyword := md.Fields().ByName("keyword")
+
+	alice := &reqctx.Principal{UserID: "alice"}
+	tests := []struct {
+		name      string
+		apiKey    string
+		principal *reqctx.Principal
+		args      string
+		want      string
+	}{
+		{"caller key fills the field", "alicekey", nil, `{"keyword":"k"}`, "alicekey"},
+		{"caller key replaces the argument", "alicekey", nil, `{"keyword":"k","apiKey":"bobkey"}`, "alicekey"},
+		{"snake case argument is replaced too", "alicekey", nil, `{"keyword":"k","api_key":"bobkey"}`, "alicekey"},
+		{"argument is cleared without a caller key", "", nil, `{"keyword":"k","apiKey":"bobkey"}`, ""},
+		{"authenticated caller's key is not forwarded", "alicekey", alice, `{"keyword":"k"}`, ""},
+		{"argument is cleared for an authenticated caller", "alicekey", alice, `{"keyword":"k","apiKey":"bobkey"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := reqctx.With(context.Background(), &reqctx.Info{APIKey: tt.apiKey, Principal: tt.principal})
+			in, err := requestMessage(ctx, md, []byte(tt.args))
+			if err != nil {
+				t.Fatalf("requestMessage: %v", err)
+			}
+			if got := in.Get(apiKey).String(); got != tt.want {
+				t.Fatalf("api_key = %q, want %q", got, tt.want)
+			}
+			if got := in.Get(keyword).String(); got != "k" {
+				t.Fatalf("keyword = %q, want %q", got, "k")
+			}
+		})
+	}
+}
+
+func TestRequestMessageRejectsBadArguments(t *testing.T) {
+	md := (&pb.SearchDiaryRequest{}).ProtoReflect().Descriptor()
+	if _, err := requestMessage(context.Background(), md, []byte(`{"keyword":1}`)); err == nil {
+		t.Fatal("requestMessage succeeded, want error for a number in a string field")
+	}
+}
+
+// startBackend 启动一个本地 gRPC 服务端，并将后端连接指向它
+func startBackend(t *testing.T, register func(*grpc.Server)) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	if err := InitClient(config.GrpcConfig{
+		BackendTarget:       lis.Addr().String(),
+		LoadBalancing:       "pick_first",
+		ReconnectMaxBackoff: time.Second,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		Close()
+		backends = nil
+	})
+}
+
+// echoFiles 返回一个未编译进本服务的 proto 文件描述符
+func echoFiles(t *testing.T) *protoregistry.Files {
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test/echo.proto"),
+		Package: proto.String("test.echo"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("EchoRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("text"),
+				JsonName: proto.String("text"),
+				Number:   proto.Int32(1),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Echo"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Say"),
+				InputType:  proto.String(".test.echo.EchoRequest"),
+				OutputType: proto.String(".test.echo.EchoRequest"),
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(fd); err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestFindMethodUsesServerReflection(t *testing.T) {
+	files := echoFiles(t)
+	startBackend(t, func(srv *grpc.Server) {
+		rpb.RegisterServerReflectionServer(srv, reflection.NewServerV1(reflection.ServerOptions{
+			Services:           srv,
+			DescriptorResolver: files,
+		}))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, name := range []string{"test.echo.Echo/Say", "/test.echo.Echo/Say", "test.echo.Echo.Say"} {
+		md, err := FindMethod(ctx, name)
+		if err != nil {
+			t.Fatalf("FindMethod(%q): %v", name, err)
+		}
+		if md.FullName() != "test.echo.Echo.Say" || md.Input().Fields().ByName("text") == nil {
+			t.Fatalf("FindMethod(%q) = %s", name, md.FullName())
+		}
+	}
+	if _, err := FindMethod(ctx, "test.echo.Echo/Missing"); err == nil {
+		t.Fatal("FindMethod succeeded for a missing method")
+	}
+}
+
+func TestFindMethodFallsBackToLinkedDescriptors(t *testing.T) {
+	// 后端未开启反射
+	startBackend(t, func(*grpc.Server) {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	md, err := FindMethod(ctx, pb.McpExtensionService_SearchDiary_FullMethodName)
+	if err != nil {
+		t.Fatalf("FindMethod: %v", err)
+	}
+	if md.FullName() != "mcp.extension.McpExtensionService.SearchDiary" {
+		t.Fatalf("FindMethod = %s", md.FullName())
+	}
+	if _, err := FindMethod(ctx, "test.echo.Echo/Say"); err == nil {
+		t.Fatal("FindMethod succeeded for a method the backend does not expose")
+	}
+}
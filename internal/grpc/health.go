@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// EndpointHealth 是一个后端副本的健康状态
+type EndpointHealth struct {
+	Target string `json:"target"`
+	// State 连接状态：IDLE、CONNECTING、READY、TRANSIENT_FAILURE、SHUTDOWN
+	State string `json:"state"`
+	// Status grpc.health.v1 的检查结果（如 SERVING）；后端未实现健康检查服务时为 Unimplemented
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// EjectedUntil 副本因连续失败被摘除时的恢复时间
+	EjectedUntil *time.Time `json:"ejectedUntil,omitempty"`
+}
+
+// Healthy 判断副本是否可用，后端未实现健康检查服务时以连接状态为准
+func (h EndpointHealth) Healthy() bool {
+	if h.EjectedUntil != nil {
+		return false
+	}
+	switch h.Status {
+	case healthpb.HealthCheckResponse_SERVING.String():
+		return true
+	case codes.Unimplemented.String():
+		return h.State == "READY" || h.State == "IDLE"
+	}
+	return false
+}
+
+// ClusterHealth 是一个后端集群的健康状态
+type ClusterHealth struct {
+	Name      string           `json:"name"`
+	Healthy   bool             `json:"healthy"`
+	Endpoints []EndpointHealth `json:"endpoints"`
+}
+
+// BackendHealth 是全部后端集群的健康状态
+type BackendHealth struct {
+	Clusters []ClusterHealth `json:"clusters"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// Healthy 判断每个集群是否都至少有一个可用副本
+func (h BackendHealth) Healthy() bool {
+	if h.Error != "" {
+		return false
+	}
+	for _, c := range h.Clusters {
+		if !c.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// Health 通过 grpc.health.v1 并发检查每个后端副本的健康状态，service 为空表示后端整体
+func Health(ctx context.Context, service string) BackendHealth {
+	if backends == nil {
+		return BackendHealth{Error: ErrNotInitialized.Error()}
+	}
+	var h BackendHealth
+	var wg sync.WaitGroup
+	for _, name := range backends.names {
+		c := backends.clusters[name]
+		ch := ClusterHealth{Name: name, Endpoints: make([]EndpointHealth, len(c.endpoints))}
+		for i, ep := range c.endpoints {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ch.Endpoints[i] = endpointHealth(ctx, c, ep, service)
+			}()
+		}
+		h.Clusters = append(h.Clusters, ch)
+	}
+	wg.Wait()
+	for i := range h.Clusters {
+		for _, ep := range h.Clusters[i].Endpoints {
+			h.Clusters[i].Healthy = h.Clusters[i].Healthy || ep.Healthy()
+		}
+	}
+	return h
+}
+
+func endpointHealth(ctx context.Context, c *cluster, ep *endpoint, service string) EndpointHealth {
+	h := EndpointHealth{Target: ep.target}
+	if until := c.ejected(ep); !until.IsZero() {
+		h.EjectedUntil = &until
+	}
+	res, err := healthpb.NewHealthClient(ep.conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		h.Status = codes.Unimplemented.String()
+	case err != nil:
+		h.Status = "UNKNOWN"
+		h.Error = err.Error()
+	default:
+		h.Status = res.GetStatus().String()
+	}
+	// 检查本身可能触发重连，以检查后的状态为准
+	h.State = ep.conn.GetState().String()
+	return h
+}
@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"mcp/internal/reqctx"
+)
+
+// 随每次调用发送给后端的 gRPC metadata 键
+const (
+	MetadataAPIKey      = "x-api-key"
+	MetadataUserID      = "x-user-id"
+	MetadataUserScopes  = "x-user-scopes"
+	MetadataRequestID   = "x-request-id"
+	MetadataSessionID   = "x-mcp-session-id"
+	MetadataTraceParent = reqctx.TraceParentHeader
+	MetadataTraceState  = reqctx.TraceStateHeader
+)
+
+// CallerMetadata 返回当前 MCP 请求的调用方身份、请求 ID 与链路追踪信息，键为上面的 Metadata* 常量，不含空值
+// ctx 中的请求信息由 MCP server 按每条消息所在的 HTTP 请求生成，而不是沿用建立会话时的请求。
+// 调用方已通过认证时只包含校验后的身份，不再包含其原始凭据；每次调用得到同一 trace 下的新 span
+func CallerMetadata(ctx context.Context) map[string]string {
+	info := reqctx.From(ctx)
+	md := make(map[string]string)
+	add := func(key, value string) {
+		if value != "" {
+			md[key] = value
+		}
+	}
+	if p := info.Principal; p != nil {
+		add(MetadataUserID, p.UserID)
+		add(MetadataUserScopes, strings.Join(p.Scopes, " "))
+	} else {
+		add(MetadataAPIKey, reqctx.BackendAPIKey(ctx))
+	}
+	add(MetadataRequestID, info.RequestID)
+	add(MetadataSessionID, info.SessionID)
+	if info.TraceParent != "" {
+		add(MetadataTraceParent, reqctx.ChildTraceParent(info.TraceParent))
+		add(MetadataTraceState, info.TraceState)
+	}
+	return md
+}
+
+// outgoingContext 将 CallerMetadata 写入 outgoing metadata，调用方已显式设置的键不会被覆盖
+func outgoingContext(ctx context.Context) context.Context {
+	existing, _ := metadata.FromOutgoingContext(ctx)
+
+	var kv []string
+	for key, value := range CallerMetadata(ctx) {
+		if len(existing.Get(key)) == 0 {
+			kv = append(kv, key, value)
+		}
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// UnaryMetadataInterceptor 为 unary 调用附加调用方 metadata
+func UnaryMetadataInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamMetadataInterceptor 为流式调用附加调用方 metadata
+func StreamMetadataInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
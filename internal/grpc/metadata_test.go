@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"mcp/internal/reqctx"
+)
+
+func TestOutgoingContext(t *testing.T) {
+	tests := []struct {
+		name string
+		info *reqctx.Info
+		want map[string]string
+	}{
+		{
+			name: "authenticated caller",
+			info: &reqctx.Info{
+				APIKey:    "alicekey",
+				Principal: &reqctx.Principal{UserID: "alice", Scopes: []string{"diary:read", "memory:read"}},
+				RequestID: "r1",
+				SessionID: "s1",
+			},
+			want: map[string]string{
+				MetadataUserID:     "alice",
+				MetadataUserScopes: "diary:read memory:read",
+				MetadataAPIKey:     "",
+				MetadataRequestID:  "r1",
+				MetadataSessionID:  "s1",
+			},
+		},
+		{
+			name: "unauthenticated caller",
+			info: &reqctx.Info{APIKey: "alicekey", RequestID: "r1"},
+			want: map[string]string{
+				MetadataAPIKey:    "alicekey",
+				MetadataUserID:    "",
+				MetadataRequestID: "r1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, _ := metadata.FromOutgoingContext(outgoingContext(reqctx.With(context.Background(), tt.info)))
+			for key, want := range tt.want {
+				if got := md.Get(key); (want == "" && len(got) != 0) || (want != "" && (len(got) != 1 || got[0] != want)) {
+					t.Errorf("%s = %v, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOutgoingContextKeepsExplicitMetadata(t *testing.T) {
+	ctx := reqctx.With(context.Background(), &reqctx.Info{RequestID: "r1"})
+	ctx = metadata.AppendToOutgoingContext(ctx, MetadataRequestID, "explicit")
+	md, _ := metadata.FromOutgoingContext(outgoingContext(ctx))
+	if got := md.Get(MetadataRequestID); len(got) != 1 || got[0] != "explicit" {
+		t.Fatalf("%s = %v, want [explicit]", MetadataRequestID, got)
+	}
+}
@@ -0,0 +1,282 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	rpbalpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectionStream 是对 v1 与 v1alpha 反射流的统一抽象
+type reflectionStream interface {
+	Send(*rpb.ServerReflectionRequest) error
+	Recv() (*rpb.ServerReflectionResponse, error)
+	CloseSend() error
+}
+
+// alphaStream 将 v1alpha 反射流适配为 v1 消息，两者的线上格式完全一致
+type alphaStream struct {
+	stream interface {
+		Send(*rpbalpha.ServerReflectionRequest) error
+		Recv() (*rpbalpha.ServerReflectionResponse, error)
+		CloseSend() error
+	}
+}
+
+func (s *alphaStream) Send(req *rpb.ServerReflectionRequest) error {
+	var alpha rpbalpha.ServerReflectionRequest
+	if err := convert(req, &alpha); err != nil {
+		return err
+	}
+	return s.stream.Send(&alpha)
+}
+
+func (s *alphaStream) Recv() (*rpb.ServerReflectionResponse, error) {
+	alpha, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	var res rpb.ServerReflectionResponse
+	if err := convert(alpha, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (s *alphaStream) CloseSend() error {
+	return s.stream.CloseSend()
+}
+
+func convert(from, to proto.Message) error {
+	b, err := proto.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, to)
+}
+
+// reflectionClient 基于 gRPC server reflection 解析后端的服务描述符
+type reflectionClient struct {
+	stream reflectionStream
+	protos map[string]*descriptorpb.FileDescriptorProto // 按文件名索引
+}
+
+// newReflectionClient 优先使用 v1 反射服务，后端未实现时回退到 v1alpha
+func newReflectionClient(ctx context.Context) (*reflectionClient, error) {
+	cc, err := GetConn()
+	if err != nil {
+		return nil, err
+	}
+	rc := &reflectionClient{protos: make(map[string]*descriptorpb.FileDescriptorProto)}
+
+	v1, err := rpb.NewServerReflectionClient(cc).ServerReflectionInfo(ctx)
+	if err == nil {
+		rc.stream = v1
+		// 流式调用的错误要到首次收发时才能得知，先用 ListServices 探测
+		if _, err = rc.listServices(); err == nil {
+			return rc, nil
+		}
+	}
+	if rc.stream != nil {
+		rc.stream.CloseSend()
+	}
+	if status.Code(err) != codes.Unimplemented {
+		return nil, fmt.Errorf("gRPC reflection failed: %w", err)
+	}
+
+	alpha, err := rpbalpha.NewServerReflectionClient(cc).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gRPC reflection failed: %w", err)
+	}
+	rc.stream = &alphaStream{stream: alpha}
+	return rc, nil
+}
+
+func (rc *reflectionClient) close() {
+	rc.stream.CloseSend()
+}
+
+func (rc *reflectionClient) roundTrip(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	if err := rc.stream.Send(req); err != nil {
+		return nil, err
+	}
+	res, err := rc.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := res.GetErrorResponse(); e != nil {
+		return nil, status.Error(codes.Code(e.ErrorCode), e.ErrorMessage)
+	}
+	return res, nil
+}
+
+// listServices 返回后端暴露的全部服务名
+func (rc *reflectionClient) listServices() ([]string, error) {
+	res, err := rc.roundTrip(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	services := make([]string, 0, len(res.GetListServicesResponse().GetService()))
+	for _, s := range res.GetListServicesResponse().GetService() {
+		services = append(services, s.Name)
+	}
+	return services, nil
+}
+
+// addFiles 记录反射服务返回的文件描述符
+func (rc *reflectionClient) addFiles(res *rpb.ServerReflectionResponse) error {
+	for _, raw := range res.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		var fd descriptorpb.FileDescriptorProto
+		if err := proto.Unmarshal(raw, &fd); err != nil {
+			return fmt.Errorf("无法解析文件描述符: %w", err)
+		}
+		rc.protos[fd.GetName()] = &fd
+	}
+	return nil
+}
+
+// fetchSymbol 拉取定义了 symbol 的文件及其全部依赖
+func (rc *reflectionClient) fetchSymbol(symbol string) error {
+	res, err := rc.roundTrip(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	})
+	if err != nil {
+		return fmt.Errorf("无法获取 %s 的描述符: %w", symbol, err)
+	}
+	if err := rc.addFiles(res); err != nil {
+		return err
+	}
+	return rc.fetchDependencies()
+}
+
+// fetchDependencies 补齐尚未拉取的依赖文件，本地已链接的文件（如 google/protobuf/*）直接复用
+func (rc *reflectionClient) fetchDependencies() error {
+	for {
+		var missing []string
+		for _, fd := range rc.protos {
+			for _, dep := range fd.GetDependency() {
+				if _, ok := rc.protos[dep]; ok {
+					continue
+				}
+				if local, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+					rc.protos[dep] = protodesc.ToFileDescriptorProto(local)
+					continue
+				}
+				missing = append(missing, dep)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		for _, name := range missing {
+			res, err := rc.roundTrip(&rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			})
+			if err != nil {
+				return fmt.Errorf("无法获取依赖文件 %s: %w", name, err)
+			}
+			if err := rc.addFiles(res); err != nil {
+				return err
+			}
+			if _, ok := rc.protos[name]; !ok {
+				return fmt.Errorf("反射服务未返回依赖文件 %s", name)
+			}
+		}
+	}
+}
+
+// files 将已拉取的文件描述符构建为可查询的注册表
+func (rc *reflectionClient) files() (*protoregistry.Files, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range rc.protos {
+		set.File = append(set.File, fd)
+	}
+	return protodesc.NewFiles(set)
+}
+
+// methodAllowed 判断方法是否在白名单中，白名单项形如 "pkg.Service/Method" 或 "pkg.Service/*"
+func methodAllowed(allow []string, md protoreflect.MethodDescriptor) bool {
+	svc := string(md.Parent().FullName())
+	for _, pattern := range allow {
+		pattern = strings.TrimPrefix(pattern, "/")
+		if pattern == svc+"/*" || pattern == svc+"/"+string(md.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedServices 提取白名单中涉及的服务名
+func allowedServices(allow []string) map[string]bool {
+	services := make(map[string]bool, len(allow))
+	for _, pattern := range allow {
+		pattern = strings.TrimPrefix(pattern, "/")
+		if i := strings.LastIndex(pattern, "/"); i > 0 {
+			services[pattern[:i]] = true
+		}
+	}
+	return services
+}
+
+// DiscoverMethods 通过 gRPC server reflection 发现后端白名单内的 unary 方法
+func DiscoverMethods(ctx context.Context, allow []string) ([]protoreflect.MethodDescriptor, error) {
+	rc, err := newReflectionClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.close()
+
+	services, err := rc.listServices()
+	if err != nil {
+		return nil, fmt.Errorf("gRPC reflection ListServices failed: %w", err)
+	}
+
+	wanted := allowedServices(allow)
+	var names []string
+	for _, svc := range services {
+		if wanted[svc] {
+			if err := rc.fetchSymbol(svc); err != nil {
+				return nil, err
+			}
+			names = append(names, svc)
+		}
+	}
+
+	files, err := rc.files()
+	if err != nil {
+		return nil, fmt.Errorf("无法构建后端描述符: %w", err)
+	}
+
+	var methods []protoreflect.MethodDescriptor
+	for _, svc := range names {
+		desc, err := files.FindDescriptorByName(protoreflect.FullName(svc))
+		if err != nil {
+			return nil, err
+		}
+		sd, ok := desc.(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+		for i := 0; i < sd.Methods().Len(); i++ {
+			md := sd.Methods().Get(i)
+			if md.IsStreamingClient() || md.IsStreamingServer() {
+				continue
+			}
+			if methodAllowed(allow, md) {
+				methods = append(methods, md)
+			}
+		}
+	}
+	return methods, nil
+}
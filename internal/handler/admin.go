@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	mcp_impl "mcp"
+)
+
+// AdminHandler handles the tool registry admin endpoints.
+type AdminHandler struct {
+	server *mcp_impl.MCPServer
+}
+
+// NewAdminHandler creates a new admin handler.
+func NewAdminHandler(server *mcp_impl.MCPServer) *AdminHandler {
+	return &AdminHandler{server: server}
+}
+
+// toolInfo is the admin view of a registered tool.
+type toolInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Scopes      []string `json:"scopes,omitempty"`
+}
+
+// ListTools handles GET /admin/tools - lists all registered tools, including disabled ones.
+func (h *AdminHandler) ListTools(c *gin.Context) {
+	entries := h.server.Tools.List()
+	tools := make([]toolInfo, 0, len(entries))
+	for _, t := range entries {
+		tools = append(tools, toolInfo{
+			Name:        t.Tool.Name,
+			Description: t.Tool.Description,
+			Enabled:     t.Enabled,
+			Scopes:      t.Scopes,
+		})
+	}
+	c.JSON(200, gin.H{"tools": tools})
+}
+
+// EnableTool handles POST /admin/tools/:name/enable.
+func (h *AdminHandler) EnableTool(c *gin.Context) {
+	if err := h.server.Tools.Enable(c.Param("name")); err != nil {
+		c.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(204)
+}
+
+// DisableTool handles POST /admin/tools/:name/disable.
+func (h *AdminHandler) DisableTool(c *gin.Context) {
+	if err := h.server.Tools.Disable(c.Param("name")); err != nil {
+		c.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(204)
+}
+
+// RemoveTool handles DELETE /admin/tools/:name.
+func (h *AdminHandler) RemoveTool(c *gin.Context) {
+	if !h.server.Tools.Remove(c.Param("name")) {
+		c.JSON(404, gin.H{"error": "tool not found: " + c.Param("name")})
+		return
+	}
+	c.Status(204)
+}
@@ -1,8 +1,46 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+	"net/http"
+	"time"
 
-// Health handles the health check endpoint.
-func Health(c *gin.Context) {
-	c.JSON(200, gin.H{"status": "ok"})
+	"github.com/gin-gonic/gin"
+
+	"mcp/internal/breaker"
+	"mcp/internal/grpc"
+)
+
+// healthCheckTimeout bounds the backend health check so /health stays responsive.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthHandler handles the health check endpoint.
+type HealthHandler struct {
+	service string
+}
+
+// NewHealthHandler creates a health handler that checks the given backend health service.
+func NewHealthHandler(service string) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// Health reports the server status, the backend connection health and the circuit breakers.
+// The server itself stays "ok" (HTTP 200) while the backend is down so liveness probes
+// don't restart it; "degraded" tells readiness checks that backend or search tools will fail.
+func (h *HealthHandler) Health(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+	defer cancel()
+
+	backend := grpc.Health(ctx, h.service)
+	breakers := breaker.All()
+	status := "ok"
+	if !backend.Healthy() {
+		status = "degraded"
+	}
+	for _, b := range breakers {
+		if b.State != breaker.StateClosed.String() {
+			status = "degraded"
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": status, "backend": backend, "breakers": breakers})
 }
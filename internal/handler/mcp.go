@@ -2,198 +2,101 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"log"
-	"time"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	mcp_impl "mcp"
+	"mcp/internal/reqctx"
 )
 
 // MCPHandler handles the Streamable HTTP MCP endpoint.
+// Session management, content negotiation and protocol-version negotiation
+// are delegated to the go-sdk StreamableHTTPHandler backed by MCPServer.Server.
 type MCPHandler struct {
-	server *mcp_impl.MCPServer
+	server     *mcp_impl.MCPServer
+	streamable *mcp.StreamableHTTPHandler
 }
 
 // NewMCPHandler creates a new MCP handler.
 func NewMCPHandler(server *mcp_impl.MCPServer) *MCPHandler {
-	return &MCPHandler{server: server}
-}
-
-// jsonrpcRequest represents a JSON-RPC 2.0 request.
-type jsonrpcRequest struct {
-	JsonRpc string          `json:"jsonrpc"`
-	Id      interface{}     `json:"id"`
-	Method  string          `json:"method"`
-	Params  json.RawMessage `json:"params,omitempty"`
-}
-
-// toolCallParams represents the parameters for tools/call method.
-type toolCallParams struct {
-	Name      string          `json:"name"`
-	Arguments json.RawMessage `json:"arguments"`
+	cfg := server.Config.Streamable
+	streamable := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return server.Server
+	}, &mcp.StreamableHTTPOptions{
+		Stateless:      cfg.Stateless,
+		JSONResponse:   cfg.JSONResponse,
+		SessionTimeout: cfg.SessionTimeout,
+	})
+	return &MCPHandler{server: server, streamable: streamable}
 }
 
 // Handle processes MCP requests via Streamable HTTP Transport.
-// Single POST endpoint with SSE response.
+//   - POST /mcp: client messages; initialize issues an Mcp-Session-Id,
+//     notifications are answered with 202, requests with JSON or SSE
+//     depending on Accept and configuration.
+//   - GET /mcp: opens the server-initiated SSE stream of a session.
+//   - DELETE /mcp: terminates a session.
+//
+// A session's context is taken from its initialize request, so the caller of
+// every later request is passed to the MCP server as the message's TokenInfo
+// (see reqctx.TokenInfo). Its UserID also binds the session to the caller
+// that initialized it: the SDK answers 403 when another caller reuses it.
 func (h *MCPHandler) Handle(c *gin.Context) {
-	body, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "failed to read body"})
+	info := reqctx.From(c.Request.Context())
+	ti := reqctx.TokenInfo(info)
+	credential := sessionCredential(c.Request, info)
+	if ti.UserID == "" || credential == "" {
+		h.streamable.ServeHTTP(c.Writer, c.Request)
 		return
 	}
 
-	var request jsonrpcRequest
-	if err := json.Unmarshal(body, &request); err != nil {
-		log.Printf("Failed to unmarshal request: %v\nBody: %s", err, string(body))
-		c.JSON(400, gin.H{"error": "invalid jsonrpc message"})
-		return
-	}
-
-	log.Printf("MCP Request: method=%s, id=%v", request.Method, request.Id)
-
-	// Set SSE response headers
-	c.Writer.Header().Set("Content-Type", "text/event-stream")
-	c.Writer.Header().Set("Cache-Control", "no-cache")
-	c.Writer.Header().Set("Connection", "keep-alive")
-	c.Writer.Flush()
-
-	// Process MCP method
-	response := h.processMethod(c, request)
-
-	// Send SSE response
-	responseBytes, _ := json.Marshal(response)
-	fmt.Fprintf(c.Writer, "data: %s\n\n", responseBytes)
-	c.Writer.Flush()
-}
-
-// processMethod routes the request to the appropriate handler.
-func (h *MCPHandler) processMethod(c *gin.Context, request jsonrpcRequest) interface{} {
-	switch request.Method {
-	case "initialize":
-		return h.handleInitialize(request.Id)
-	case "tools/list":
-		return h.handleToolsList(request.Id)
-	case "tools/call":
-		return h.handleToolsCall(c, request.Id, request.Params)
-	case "ping":
-		return h.handlePing(request.Id)
-	default:
-		return h.errorResponse(request.Id, -32601, "Method not found")
-	}
-}
-
-// handlePing handles the ping method.
-func (h *MCPHandler) handlePing(id interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"result":  map[string]interface{}{},
-	}
-}
-
-// handleInitialize handles the initialize method.
-func (h *MCPHandler) handleInitialize(id interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"result": map[string]interface{}{
-			"protocolVersion": "2024-11-05",
-			"capabilities": map[string]interface{}{
-				"tools": map[string]interface{}{},
-			},
-			"serverInfo": map[string]interface{}{
-				"name":    "yusi-mcp-server",
-				"version": "1.0.0",
-			},
-		},
-	}
-}
-
-// handleToolsList handles the tools/list method.
-func (h *MCPHandler) handleToolsList(id interface{}) map[string]interface{} {
-	tools := h.server.GetTools()
-	toolList := make([]map[string]interface{}, 0, len(tools))
-	for _, tool := range tools {
-		toolList = append(toolList, map[string]interface{}{
-			"name":        tool.Name,
-			"description": tool.Description,
-			"inputSchema": tool.InputSchema,
-		})
-	}
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"result": map[string]interface{}{
-			"tools": toolList,
-		},
-	}
-}
-
-// handleToolsCall handles the tools/call method.
-func (h *MCPHandler) handleToolsCall(c *gin.Context, id interface{}, params json.RawMessage) map[string]interface{} {
-	var callParams toolCallParams
-	if err := json.Unmarshal(params, &callParams); err != nil {
-		return h.errorResponse(id, -32602, "Invalid params")
+	// The SDK accepts TokenInfo only from its bearer-token verifier. The caller is
+	// already authenticated, so the verifier maps the credential of this request
+	// to ti. Callers that sent it elsewhere are given it as a bearer token on a
+	// copy of the request; the original request is left untouched.
+	r := c.Request
+	if bearerToken(r) != credential {
+		r = r.Clone(r.Context())
+		r.Header.Set("Authorization", "Bearer "+credential)
 	}
-
-	// 从中间件中获取 apiKey
-	apiKey := c.GetString("apiKey")
-	
-	// 如果中间件没有提取到（理论上不可能，如果中间件正确运行），尝试手动提取作为后备
-	if apiKey == "" {
-		apiKey = c.Query("api_key")
-		if apiKey == "" {
-			apiKey = c.GetHeader("X-API-Key")
-		}
-		if apiKey == "" {
-			apiKey = c.GetHeader("Authorization")
-			if len(apiKey) > 7 && apiKey[:7] == "Bearer " {
-				apiKey = apiKey[7:]
-			}
+	verify := func(_ context.Context, token string, _ *http.Request) (*sdkauth.TokenInfo, error) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(credential)) != 1 {
+			return nil, sdkauth.ErrInvalidToken
 		}
+		return ti, nil
 	}
+	sdkauth.RequireBearerToken(verify, nil)(h.streamable).ServeHTTP(c.Writer, r)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	ctx = context.WithValue(ctx, "apiKey", apiKey)
-
-	result, err := h.server.CallTool(ctx, callParams.Name, callParams.Arguments)
-	if err != nil {
-		return h.errorResponse(id, -32603, err.Error())
+// sessionCredential returns the credential the request was authenticated with:
+// the API Key, the OAuth access token, or the fingerprint of the verified client
+// certificate. It is empty for anonymous requests.
+func sessionCredential(r *http.Request, info *reqctx.Info) string {
+	if info.APIKey != "" {
+		return info.APIKey
 	}
-
-	// Convert MCP result to JSON
-	content := make([]map[string]interface{}, 0)
-	for _, c := range result.Content {
-		content = append(content, map[string]interface{}{
-			"type": "text",
-			"text": fmt.Sprintf("%v", c),
-		})
+	if token := bearerToken(r); token != "" && info.Principal != nil {
+		return token
 	}
-
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"result": map[string]interface{}{
-			"content": content,
-			"isError": result.IsError,
-		},
+	if tls := r.TLS; tls != nil && len(tls.VerifiedChains) > 0 && len(tls.VerifiedChains[0]) > 0 {
+		sum := sha256.Sum256(tls.VerifiedChains[0][0].Raw)
+		return "cert:" + hex.EncodeToString(sum[:])
 	}
+	return ""
 }
 
-// errorResponse creates a JSON-RPC error response.
-func (h *MCPHandler) errorResponse(id interface{}, code int, message string) map[string]interface{} {
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      id,
-		"error": map[string]interface{}{
-			"code":    code,
-			"message": message,
-		},
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
 	}
+	return strings.TrimSpace(token)
 }
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	mcp_impl "mcp"
+	"mcp/config"
+	"mcp/internal/auth"
+	"mcp/internal/middleware"
+)
+
+const initializeBody = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+
+// newTestMCPEngine 创建带认证中间件的 /mcp 路由，alice 与 bob 各持有一个 API Key，
+// 也可以使用主题 CN 为各自名字的客户端证书；after 在处理完每个请求后调用
+func newTestMCPEngine(t *testing.T, after func(*gin.Context)) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	server := &mcp_impl.MCPServer{
+		Server: mcp.NewServer(&mcp.Implementation{Name: "test"}, nil),
+		Config: &config.MCPConfig{},
+	}
+	store := auth.NewStaticKeyStore([]config.APIKeyConfig{
+		{Key: "alice-key", UserID: "alice"},
+		{Key: "bob-key", UserID: "bob"},
+	})
+	r := gin.New()
+	r.Use(middleware.RequestContext())
+	h := NewMCPHandler(server)
+	r.POST("/mcp", middleware.ClientCert(auth.ClientCertPrincipals{}), middleware.Authenticate(store, ""), func(c *gin.Context) {
+		h.Handle(c)
+		if after != nil {
+			after(c)
+		}
+	})
+	return r
+}
+
+func postMCP(r *gin.Engine, sessionID, body string, caller func(*http.Request)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Mcp-Protocol-Version", "2025-06-18")
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	caller(req)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMCPSessionBoundToCaller(t *testing.T) {
+	tests := []struct {
+		name       string
+		initialize func(*http.Request)
+		reuse      func(*http.Request)
+		wantStatus int
+	}{
+		{"same key as bearer token", bearer("alice-key"), bearer("alice-key"), http.StatusAccepted},
+		{"same key sent differently", apiKeyHeader("alice-key"), bearer("alice-key"), http.StatusAccepted},
+		{"raw authorization header", rawAuthorization("alice-key"), apiKeyHeader("alice-key"), http.StatusAccepted},
+		{"same client certificate", clientCert("alice"), clientCert("alice"), http.StatusAccepted},
+		{"certificate then key of the same user", clientCert("alice"), apiKeyHeader("alice-key"), http.StatusAccepted},
+		{"another client certificate", clientCert("alice"), clientCert("bob"), http.StatusForbidden},
+		{"another caller", bearer("alice-key"), bearer("bob-key"), http.StatusForbidden},
+		{"another caller with X-API-Key", apiKeyHeader("alice-key"), apiKeyHeader("bob-key"), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestMCPEngine(t, nil)
+			w := postMCP(r, "", initializeBody, tt.initialize)
+			sessionID := w.Header().Get("Mcp-Session-Id")
+			if w.Code != http.StatusOK || sessionID == "" {
+				t.Fatalf("initialize: status %d, session %q, body %s", w.Code, sessionID, w.Body)
+			}
+
+			w = postMCP(r, sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`, tt.reuse)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("reuse: status %d, want %d, body %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestMCPHandleKeepsCallerHeaders(t *testing.T) {
+	var authorization []string
+	r := newTestMCPEngine(t, func(c *gin.Context) {
+		authorization = c.Request.Header.Values("Authorization")
+	})
+
+	// 凭据以 Bearer 形式交给 SDK 时使用请求副本，调用方的请求头保持原样
+	if w := postMCP(r, "", initializeBody, apiKeyHeader("alice-key")); w.Code != http.StatusOK {
+		t.Fatalf("initialize: status %d, body %s", w.Code, w.Body)
+	}
+	if len(authorization) != 0 {
+		t.Fatalf("Authorization = %q, want none", authorization)
+	}
+}
+
+func bearer(key string) func(*http.Request) {
+	return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+key) }
+}
+
+func rawAuthorization(key string) func(*http.Request) {
+	return func(r *http.Request) { r.Header.Set("Authorization", key) }
+}
+
+func apiKeyHeader(key string) func(*http.Request) {
+	return func(r *http.Request) { r.Header.Set("X-API-Key", key) }
+}
+
+// clientCert 模拟经过 mTLS 校验的客户端证书
+func clientCert(cn string) func(*http.Request) {
+	cert := &x509.Certificate{Raw: []byte("cert:" + cn), Subject: pkix.Name{CommonName: cn}}
+	return func(r *http.Request) {
+		r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mcp/internal/breaker"
+)
+
+// breakerStates lists every state so each breaker exports one series per state.
+var breakerStates = []breaker.State{breaker.StateClosed, breaker.StateHalfOpen, breaker.StateOpen}
+
+// Metrics handles GET /metrics, exposing the circuit breakers in the Prometheus text format.
+func Metrics(c *gin.Context) {
+	var sb strings.Builder
+	snapshots := breaker.All()
+
+	sb.WriteString("# HELP mcp_circuit_breaker_state Current circuit breaker state (1 for the active state).\n")
+	sb.WriteString("# TYPE mcp_circuit_breaker_state gauge\n")
+	for _, s := range snapshots {
+		for _, state := range breakerStates {
+			v := 0
+			if s.State == state.String() {
+				v = 1
+			}
+			fmt.Fprintf(&sb, "mcp_circuit_breaker_state{breaker=%q,state=%q} %d\n", s.Name, state, v)
+		}
+	}
+
+	counters := []struct {
+		name, help string
+		value      func(breaker.Stats) uint64
+	}{
+		{"mcp_circuit_breaker_calls_total", "Calls let through by the circuit breaker.", func(s breaker.Stats) uint64 { return s.Calls }},
+		{"mcp_circuit_breaker_failures_total", "Failed calls, including slow calls.", func(s breaker.Stats) uint64 { return s.Failures }},
+		{"mcp_circuit_breaker_slow_calls_total", "Calls slower than the slow call threshold.", func(s breaker.Stats) uint64 { return s.SlowCalls }},
+		{"mcp_circuit_breaker_rejected_total", "Calls rejected while the circuit breaker was open.", func(s breaker.Stats) uint64 { return s.Rejected }},
+		{"mcp_circuit_breaker_opened_total", "Times the circuit breaker opened.", func(s breaker.Stats) uint64 { return s.Opened }},
+	}
+	for _, m := range counters {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s counter\n", m.name, m.help, m.name)
+		for _, s := range snapshots {
+			fmt.Fprintf(&sb, "%s{breaker=%q} %d\n", m.name, s.Name, m.value(s.Stats))
+		}
+	}
+
+	c.Data(200, "text/plain; version=0.0.4; charset=utf-8", []byte(sb.String()))
+}
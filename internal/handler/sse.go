@@ -1,15 +1,16 @@
 package handler
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 
 	"github.com/gin-gonic/gin"
 	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	mcp_impl "mcp"
+	"mcp/internal/reqctx"
 )
 
 // SSEHandler handles the legacy SSE transport endpoints.
@@ -23,9 +24,28 @@ func NewSSEHandler(server *mcp_impl.MCPServer) *SSEHandler {
 }
 
 // Connect handles GET /sse - establishes SSE connection.
+// Each connection runs its own MCP server session until the client disconnects.
 func (h *SSEHandler) Connect(c *gin.Context) {
 	transport := mcp_impl.NewSSEServerTransport()
 
+	// 会话的 context 来自该连接；每条消息的请求 ID 与调用方随 /messages 请求另行传入
+	info := reqctx.From(c.Request.Context()).Clone()
+	info.Transport = reqctx.TransportSSE
+	transport.Info = info
+	ctx := reqctx.With(c.Request.Context(), info)
+
+	session, err := h.server.Server.Connect(ctx, transport, nil)
+	if err != nil {
+		transport.Close()
+		log.Printf("Failed to start MCP session (legacy SSE): %v", err)
+		c.JSON(500, gin.H{"error": "failed to start session"})
+		return
+	}
+	// 关闭 session 会同时关闭 transport 并将其从 TransportMap 中移除
+	defer session.Close()
+
+	log.Printf("MCP Server transport connected (legacy SSE), session=%s", transport.SessionID())
+
 	c.Writer.Header().Set("Content-Type", "text/event-stream")
 	c.Writer.Header().Set("Cache-Control", "no-cache")
 	c.Writer.Header().Set("Connection", "keep-alive")
@@ -35,21 +55,25 @@ func (h *SSEHandler) Connect(c *gin.Context) {
 	c.SSEvent("endpoint", endpoint)
 	c.Writer.Flush()
 
-	// Start serving in a goroutine
-	go func() {
-		apiKey := c.GetString("apiKey")
-		log.Printf("MCP Server transport connected (legacy SSE), apiKey=%s", apiKey)
-		// 这里的 Server 应该启动来处理 transport 上的消息
-		// h.server.Server.ServeStream(transport) 
-	}()
-
 	// Stream messages from transport to SSE
-	for msg := range transport.SendChan {
-		c.SSEvent("message", msg)
-		c.Writer.Flush()
+	for {
+		select {
+		case msg := <-transport.SendChan:
+			data, err := jsonrpc.EncodeMessage(msg.(jsonrpc.Message))
+			if err != nil {
+				log.Printf("Failed to encode message for session %s: %v", transport.SessionID(), err)
+				continue
+			}
+			c.SSEvent("message", string(data))
+			c.Writer.Flush()
+		case <-transport.Done():
+			log.Printf("SSE session %s ended by server", transport.SessionID())
+			return
+		case <-c.Request.Context().Done():
+			log.Printf("SSE connection closed for session %s", transport.SessionID())
+			return
+		}
 	}
-
-	log.Printf("SSE connection closed for session %s", transport.SessionID())
 }
 
 // Message handles POST /messages - receives messages for a session.
@@ -67,19 +91,37 @@ func (h *SSEHandler) Message(c *gin.Context) {
 	}
 	transport := val.(*mcp_impl.SSEServerTransport)
 
+	// 会话绑定到建立 SSE 连接的调用方，与 Streamable HTTP 一致拒绝其他调用方
+	info := reqctx.From(c.Request.Context())
+	if reqctx.SessionUser(info) != reqctx.SessionUser(transport.Info) {
+		c.JSON(403, gin.H{"error": "session user mismatch"})
+		return
+	}
+
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "failed to read body"})
 		return
 	}
 
-	var msg jsonrpc.Message
-	if err := json.Unmarshal(body, &msg); err != nil {
+	msg, err := jsonrpc.DecodeMessage(body)
+	if err != nil {
 		log.Printf("Failed to unmarshal message: %v\nBody: %s", err, string(body))
 		c.JSON(400, gin.H{"error": "invalid jsonrpc message"})
 		return
 	}
 
-	transport.HandleMessage(msg)
-	c.Status(200)
+	// 与 Streamable HTTP 一致，通过 RequestExtra 将本次请求的调用方与请求头交给 MCP server
+	if req, ok := msg.(*jsonrpc.Request); ok {
+		req.Extra = &mcp.RequestExtra{
+			TokenInfo: reqctx.TokenInfo(info),
+			Header:    c.Request.Header,
+		}
+	}
+
+	if err := transport.HandleMessage(c.Request.Context(), msg); err != nil {
+		c.JSON(404, gin.H{"error": "session closed"})
+		return
+	}
+	c.Status(202)
 }
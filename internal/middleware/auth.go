@@ -1,10 +1,17 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"mcp/internal/auth"
+	"mcp/internal/reqctx"
+	"mcp/pkg/log"
 )
 
 // Auth returns a middleware that checks for API Key.
@@ -19,29 +26,7 @@ import (
 // If expectedKey is empty, it allows any key (or no key) and just stores it if present.
 func Auth(expectedKey string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var clientKey string
-
-		// 1. Check Query Parameter
-		clientKey = c.Query("api_key")
-
-		// 2. Check Authorization Header
-		if clientKey == "" {
-			authHeader := c.GetHeader("Authorization")
-			if authHeader != "" {
-				parts := strings.SplitN(authHeader, " ", 2)
-				if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
-					clientKey = parts[1]
-				} else {
-					// Some clients might send the key directly in Authorization header
-					clientKey = authHeader
-				}
-			}
-		}
-
-		// 3. Check X-API-Key Header
-		if clientKey == "" {
-			clientKey = c.GetHeader("X-API-Key")
-		}
+		clientKey := extractAPIKey(c)
 
 		// Store the key in context for downstream handlers/tools
 		if clientKey != "" {
@@ -51,7 +36,8 @@ func Auth(expectedKey string) gin.HandlerFunc {
 		// Validation logic
 		if expectedKey != "" {
 			// If server is configured with a key, enforce it
-			if clientKey == "" || clientKey != expectedKey {
+			// Constant-time comparison so response timing does not reveal the key
+			if clientKey == "" || subtle.ConstantTimeCompare([]byte(clientKey), []byte(expectedKey)) != 1 {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Invalid or missing API Key"})
 				return
 			}
@@ -62,3 +48,107 @@ func Auth(expectedKey string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Authenticate returns a middleware that validates the API Key or OAuth access token against the key store.
+// Requests without a valid credential are rejected with 401 before reaching any tool,
+// unless a client certificate was already accepted by ClientCert.
+// On success the resolved principal is stored in the context as "principal"
+// and attached to the request context (see reqctx.Principal).
+//
+// If resourceMetadataURL is non-empty (OAuth resource-server mode), credentials are only
+// accepted as "Authorization: Bearer <token>", 401 responses carry a WWW-Authenticate
+// challenge pointing clients at the protected resource metadata, and the credential is
+// dropped from the request context: access tokens are issued for this server and must not
+// be passed through to the backend, which receives the verified principal instead.
+func Authenticate(store auth.KeyStore, resourceMetadataURL string) gin.HandlerFunc {
+	oauth := resourceMetadataURL != ""
+	return func(c *gin.Context) {
+		clientKey := extractAPIKey(c)
+		if oauth {
+			clientKey = extractBearerToken(c)
+			info := reqctx.From(c.Request.Context()).Clone()
+			info.APIKey = ""
+			c.Request = c.Request.WithContext(reqctx.With(c.Request.Context(), info))
+		}
+		if clientKey == "" && reqctx.From(c.Request.Context()).Principal != nil {
+			// 已通过客户端证书认证
+			c.Next()
+			return
+		}
+		if clientKey == "" {
+			unauthorized(c, resourceMetadataURL, "")
+			return
+		}
+
+		principal, err := store.Lookup(c.Request.Context(), clientKey)
+		if errors.Is(err, auth.ErrInvalidKey) {
+			unauthorized(c, resourceMetadataURL, "invalid_token")
+			return
+		}
+		if err != nil {
+			log.Error("API Key 校验失败", "error", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "authentication service unavailable"})
+			return
+		}
+
+		c.Set("principal", principal)
+		info := reqctx.From(c.Request.Context()).Clone()
+		if !oauth {
+			info.APIKey = clientKey
+		}
+		info.Principal = principal
+		c.Request = c.Request.WithContext(reqctx.With(c.Request.Context(), info))
+		c.Next()
+	}
+}
+
+// unauthorized aborts with 401 and, in OAuth mode, a Bearer challenge (RFC 6750, RFC 9728).
+// errorCode is empty when no credential was presented.
+func unauthorized(c *gin.Context, resourceMetadataURL, errorCode string) {
+	if resourceMetadataURL != "" {
+		challenge := fmt.Sprintf("Bearer resource_metadata=%q", resourceMetadataURL)
+		if errorCode != "" {
+			challenge += fmt.Sprintf(", error=%q", errorCode)
+		}
+		c.Header("WWW-Authenticate", challenge)
+	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Invalid or missing API Key"})
+}
+
+// extractAPIKey extracts the API Key from:
+// 1. Query parameter: "api_key=<key>"
+// 2. Authorization header: "Bearer <key>" or just "<key>"
+// 3. X-API-Key header: "<key>"
+func extractAPIKey(c *gin.Context) string {
+	// 1. Check Query Parameter
+	clientKey := c.Query("api_key")
+
+	// 2. Check Authorization Header
+	if clientKey == "" {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader != "" {
+			parts := strings.SplitN(authHeader, " ", 2)
+			if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+				clientKey = parts[1]
+			} else {
+				// Some clients might send the key directly in Authorization header
+				clientKey = authHeader
+			}
+		}
+	}
+
+	// 3. Check X-API-Key Header
+	if clientKey == "" {
+		clientKey = c.GetHeader("X-API-Key")
+	}
+	return clientKey
+}
+
+// extractBearerToken extracts the token from an "Authorization: Bearer <token>" header (RFC 6750).
+func extractBearerToken(c *gin.Context) string {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
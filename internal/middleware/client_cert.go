@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"mcp/internal/auth"
+	"mcp/internal/reqctx"
+)
+
+// ClientCert returns a middleware that maps a verified mTLS client certificate to a principal.
+// Authenticate accepts such requests without an API Key; a presented API Key still takes precedence.
+func ClientCert(principals auth.ClientCertPrincipals) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tls := c.Request.TLS; tls != nil && len(tls.VerifiedChains) > 0 && len(tls.VerifiedChains[0]) > 0 {
+			principal := principals.Lookup(tls.VerifiedChains[0][0])
+			info := reqctx.From(c.Request.Context()).Clone()
+			info.Principal = principal
+			c.Set("principal", principal)
+			c.Request = c.Request.WithContext(reqctx.With(c.Request.Context(), info))
+		}
+		c.Next()
+	}
+}
@@ -1,19 +1,143 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mcp/config"
+)
+
+// CORS returns a middleware that handles Cross-Origin Resource Sharing according to cfg.
+// CORS headers are only sent for allowed origins; preflight requests from other origins are rejected with 403.
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	policy := newOriginPolicy(cfg.AllowedOrigins)
+	allowHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
 
-// CORS returns a middleware that handles Cross-Origin Resource Sharing.
-func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept")
+		origin := c.GetHeader("Origin")
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+
+		allowed := origin != "" && policy.allows(origin)
+		if allowed {
+			// 允许携带凭据时不能使用通配符
+			if policy.any && !cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+			if exposeHeaders != "" {
+				h.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if c.Request.Method == http.MethodOptions {
+			if origin != "" && !allowed {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			if allowed {
+				h.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+				h.Set("Access-Control-Allow-Headers", allowHeaders)
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// ValidateOrigin returns a middleware that rejects requests whose Origin header is not allowed,
+// protecting local deployments against DNS rebinding as required by the MCP transport spec.
+// Requests without an Origin header (non-browser clients) are allowed.
+func ValidateOrigin(cfg config.CORSConfig) gin.HandlerFunc {
+	policy := newOriginPolicy(cfg.AllowedOrigins)
+	return func(c *gin.Context) {
+		if origin := c.GetHeader("Origin"); origin != "" && !policy.allows(origin) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: origin not allowed"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// originPattern is a parsed allowed origin: host may start with "*." and port may be "*".
+type originPattern struct {
+	scheme, host, port string
+}
+
+type originPolicy struct {
+	any      bool
+	patterns []originPattern
+}
+
+func newOriginPolicy(origins []string) *originPolicy {
+	p := &originPolicy{}
+	for _, o := range origins {
+		if o == "*" {
+			p.any = true
+			continue
+		}
+		if pat, ok := parseOrigin(o); ok {
+			p.patterns = append(p.patterns, pat)
+		}
+	}
+	return p
+}
+
+func (p *originPolicy) allows(origin string) bool {
+	if p.any {
+		return true
+	}
+	o, ok := parseOrigin(origin)
+	if !ok {
+		return false
+	}
+	for _, pat := range p.patterns {
+		if pat.scheme != o.scheme {
+			continue
+		}
+		if pat.port != "*" && pat.port != o.port {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(pat.host, "*"); ok {
+			if strings.HasSuffix(o.host, suffix) && len(o.host) > len(suffix) {
+				return true
+			}
+		} else if pat.host == o.host {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOrigin splits "scheme://host[:port]" and fills in the default port for http and https.
+func parseOrigin(origin string) (originPattern, bool) {
+	scheme, rest, ok := strings.Cut(strings.ToLower(strings.TrimSuffix(origin, "/")), "://")
+	if !ok || rest == "" || strings.ContainsAny(rest, "/?#@") {
+		return originPattern{}, false
+	}
+	host, port := rest, ""
+	if i := strings.LastIndex(rest, ":"); i >= 0 && !strings.HasSuffix(rest, "]") {
+		host, port = rest[:i], rest[i+1:]
+	}
+	if port == "" {
+		switch scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	return originPattern{scheme: scheme, host: host, port: port}, true
+}
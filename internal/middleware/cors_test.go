@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"mcp/config"
+)
+
+func TestOriginPolicyAllows(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{"wildcard allows any origin", []string{"*"}, "https://evil.example", true},
+		{"wildcard allows null", []string{"*"}, "null", true},
+		{"exact match", []string{"https://app.example.com"}, "https://app.example.com", true},
+		{"match is case-insensitive", []string{"https://app.example.com"}, "HTTPS://App.Example.com", true},
+		{"trailing slash is ignored", []string{"https://app.example.com/"}, "https://app.example.com", true},
+		{"default port is implied", []string{"https://app.example.com"}, "https://app.example.com:443", true},
+		{"scheme mismatch", []string{"https://app.example.com"}, "http://app.example.com", false},
+		{"port mismatch", []string{"http://localhost:3000"}, "http://localhost:3001", false},
+		{"explicit port against default", []string{"https://app.example.com"}, "https://app.example.com:8443", false},
+		{"port wildcard", []string{"http://localhost:*"}, "http://localhost:5173", true},
+		{"port wildcard covers the default port", []string{"http://localhost:*"}, "http://localhost", true},
+		{"port wildcard keeps the host", []string{"http://localhost:*"}, "http://127.0.0.1:5173", false},
+		{"subdomain wildcard", []string{"https://*.example.com"}, "https://app.example.com", true},
+		{"subdomain wildcard nested", []string{"https://*.example.com"}, "https://a.b.example.com", true},
+		{"subdomain wildcard excludes the apex", []string{"https://*.example.com"}, "https://example.com", false},
+		{"subdomain wildcard is label-aligned", []string{"https://*.example.com"}, "https://evilexample.com", false},
+		{"IPv6 host", []string{"http://[::1]:8080"}, "http://[::1]:8080", true},
+		{"IPv6 host with default port", []string{"http://[::1]"}, "http://[::1]:80", true},
+		{"null origin", []string{"http://localhost:*"}, "null", false},
+		{"origin with a path", []string{"https://app.example.com"}, "https://app.example.com/x", false},
+		{"origin with userinfo", []string{"https://app.example.com"}, "https://user@app.example.com", false},
+		{"malformed allowed origins are skipped", []string{"app.example.com", "https://ok.example.com"}, "https://ok.example.com", true},
+		{"no allowed origins", nil, "https://app.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOriginPolicy(tt.allowed).allows(tt.origin); got != tt.want {
+				t.Fatalf("allows(%q) with %v = %v, want %v", tt.origin, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ValidateOrigin(config.CORSConfig{AllowedOrigins: []string{"http://localhost:*", "https://*.example.com"}}))
+	r.POST("/mcp", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name   string
+		origin string
+		want   int
+	}{
+		{"no Origin header", "", http.StatusOK},
+		{"allowed origin", "http://localhost:6274", http.StatusOK},
+		{"allowed subdomain", "https://app.example.com", http.StatusOK},
+		{"rebinding attacker", "http://attacker.test", http.StatusForbidden},
+		{"port on a different scheme", "https://localhost:6274", http.StatusForbidden},
+		{"null origin", "null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
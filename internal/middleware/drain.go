@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RefuseNewSessions returns a middleware that rejects requests opening a new MCP session
+// (GET /sse, or /mcp without an Mcp-Session-Id header) with 503 while draining() is true.
+// Requests on existing sessions (/mcp with an Mcp-Session-Id header, or POST /messages
+// with a sessionId query) continue to be served so in-flight work can complete.
+func RefuseNewSessions(draining func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if draining() && !existingSession(c) {
+			c.Header("Connection", "close")
+			c.Header("Retry-After", "5")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "server is shutting down"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// existingSession reports whether the request belongs to an already established session.
+func existingSession(c *gin.Context) bool {
+	switch c.FullPath() {
+	case "/mcp":
+		return c.GetHeader("Mcp-Session-Id") != ""
+	case "/messages":
+		return c.Request.Method == http.MethodPost && c.Query("sessionId") != ""
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRefuseNewSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	draining := false
+	r := gin.New()
+	r.Use(RefuseNewSessions(func() bool { return draining }))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.POST("/mcp", ok)
+	r.GET("/mcp", ok)
+	r.DELETE("/mcp", ok)
+	r.GET("/sse", ok)
+	r.POST("/messages", ok)
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		sessionID string
+		want      int
+	}{
+		{"new streamable session", http.MethodPost, "/mcp", "", http.StatusServiceUnavailable},
+		{"existing streamable session", http.MethodPost, "/mcp", "s1", http.StatusOK},
+		{"stream on existing session", http.MethodGet, "/mcp", "s1", http.StatusOK},
+		{"close existing session", http.MethodDelete, "/mcp", "s1", http.StatusOK},
+		{"new SSE session", http.MethodGet, "/sse", "", http.StatusServiceUnavailable},
+		{"new SSE session with a sessionId query", http.MethodGet, "/sse?sessionId=s1", "", http.StatusServiceUnavailable},
+		{"new SSE session with a session header", http.MethodGet, "/sse", "s1", http.StatusServiceUnavailable},
+		{"message on existing SSE session", http.MethodPost, "/messages?sessionId=s1", "", http.StatusOK},
+		{"message without a session", http.MethodPost, "/messages", "", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, draining = range []bool{false, true} {
+				req := httptest.NewRequest(tt.method, tt.target, nil)
+				if tt.sessionID != "" {
+					req.Header.Set("Mcp-Session-Id", tt.sessionID)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				want := tt.want
+				if !draining {
+					want = http.StatusOK
+				}
+				if w.Code != want {
+					t.Fatalf("draining=%v: status = %d, want %d", draining, w.Code, want)
+				}
+			}
+		})
+	}
+}
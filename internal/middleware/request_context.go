@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"mcp/internal/reqctx"
+)
+
+// RequestIDHeader is the header carrying the request ID in both directions.
+const RequestIDHeader = reqctx.RequestIDHeader
+
+// RequestContext returns a middleware that attaches a reqctx.Info to the request context.
+// The request ID is taken from the X-Request-Id header (or generated) and echoed back
+// in the response; the API Key is extracted the same way as in Auth.
+// W3C trace context headers are kept so they can be forwarded to the backend;
+// a new root traceparent is started when the client sends none.
+// The generated values are written back into the request headers, because MCP messages on an
+// existing session only see the headers of their own HTTP request (RequestExtra.Header).
+// Authenticate later fills in the principal, the MCP server fills in session and client info.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = reqctx.NewRequestID()
+			c.Request.Header.Set(RequestIDHeader, requestID)
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		traceParent := c.GetHeader(reqctx.TraceParentHeader)
+		traceState := c.GetHeader(reqctx.TraceStateHeader)
+		if !reqctx.ValidTraceParent(traceParent) {
+			traceParent, traceState = reqctx.NewTraceParent(), ""
+			c.Request.Header.Set(reqctx.TraceParentHeader, traceParent)
+			c.Request.Header.Del(reqctx.TraceStateHeader)
+		}
+
+		info := &reqctx.Info{
+			RequestID:   requestID,
+			APIKey:      extractAPIKey(c),
+			RemoteIP:    c.ClientIP(),
+			Transport:   reqctx.TransportStreamable,
+			TraceParent: traceParent,
+			TraceState:  traceState,
+		}
+		c.Request = c.Request.WithContext(reqctx.With(c.Request.Context(), info))
+		c.Next()
+	}
+}
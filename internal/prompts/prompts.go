@@ -0,0 +1,235 @@
+package prompts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"mcp/config"
+	"mcp/pkg/log"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolCaller 在服务端内部调用已注册的工具，用于预填充
+type ToolCaller func(ctx context.Context, name string, argsJSON []byte) (*mcp.CallToolResult, error)
+
+// PrefillResult 是一次预填充工具调用的结果，在消息模板中以 .prefill.<as> 引用
+type PrefillResult struct {
+	// Text 工具返回的文本内容
+	Text string
+	// Data 工具返回的结构化内容，没有时为 nil
+	Data any
+	// IsError 工具调用是否失败，失败时 Text 为错误信息
+	IsError bool
+}
+
+// timeLayout 与后端 gRPC 接口约定的时间格式一致
+const timeLayout = "2006-01-02 15:04:05"
+
+// templateFuncs 是提示词模板中可用的辅助函数
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+	// now 返回当前时间，格式为 yyyy-MM-dd HH:mm:ss
+	"now": func() string {
+		return time.Now().Format(timeLayout)
+	},
+	// daysAgo 返回 n 天前的零点，格式为 yyyy-MM-dd HH:mm:ss
+	"daysAgo": func(n int) string {
+		y, m, d := time.Now().AddDate(0, 0, -n).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.Local).Format(timeLayout)
+	},
+}
+
+// PromptTemplate 是在配置或模板文件中定义的提示词，由服务端渲染
+type PromptTemplate struct {
+	Config   config.PromptTemplateConfig
+	prefill  []*template.Template
+	messages []*template.Template
+	callTool ToolCaller
+}
+
+// NewPromptTemplate 根据配置创建提示词模板
+func NewPromptTemplate(cfg config.PromptTemplateConfig, callTool ToolCaller) (*PromptTemplate, error) {
+	p := &PromptTemplate{Config: cfg, callTool: callTool}
+
+	for i, pf := range cfg.Prefill {
+		tmpl, err := template.New(fmt.Sprintf("%s.prefill[%d]", cfg.Name, i)).Funcs(templateFuncs).Parse(pf.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("提示词 %s 的 prefill 参数模板非法: %w", cfg.Name, err)
+		}
+		p.prefill = append(p.prefill, tmpl)
+	}
+	for i, m := range cfg.Messages {
+		tmpl, err := template.New(fmt.Sprintf("%s.messages[%d]", cfg.Name, i)).Funcs(templateFuncs).Parse(m.Text)
+		if err != nil {
+			return nil, fmt.Errorf("提示词 %s 的消息模板非法: %w", cfg.Name, err)
+		}
+		p.messages = append(p.messages, tmpl)
+	}
+	return p, nil
+}
+
+// GetPromptDef 返回该提示词在 MCP 中注册的定义
+func (p *PromptTemplate) GetPromptDef() *mcp.Prompt {
+	args := make([]*mcp.PromptArgument, 0, len(p.Config.Arguments))
+	for _, a := range p.Config.Arguments {
+		args = append(args, &mcp.PromptArgument{
+			Name:        a.Name,
+			Description: argumentDescription(a),
+			Required:    a.Required && a.Default == "",
+		})
+	}
+	return &mcp.Prompt{
+		Name:        p.Config.Name,
+		Title:       p.Config.Title,
+		Description: p.Config.Description,
+		Arguments:   args,
+	}
+}
+
+// argumentDescription 在参数描述后附上类型、取值与默认值提示
+// MCP 的提示词参数只能是字符串，客户端只能从描述中得知其类型
+func argumentDescription(a config.PromptArgumentConfig) string {
+	var hints []string
+	switch a.Type {
+	case "string":
+	case "enum":
+		hints = append(hints, "可选值: "+strings.Join(a.Enum, ", "))
+	case "date":
+		hints = append(hints, "日期，格式 yyyy-MM-dd")
+	default:
+		hints = append(hints, "类型: "+a.Type)
+	}
+	if a.Default != "" {
+		hints = append(hints, "默认值: "+a.Default)
+	}
+	if len(hints) == 0 {
+		return a.Description
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (%s)", a.Description, strings.Join(hints, "; ")))
+}
+
+// Handle 处理 prompts/get：校验参数、执行预填充并渲染消息
+func (p *PromptTemplate) Handle(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	data, err := p.arguments(req.Params.Arguments)
+	if err != nil {
+		return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: err.Error()}
+	}
+
+	prefill := make(map[string]PrefillResult, len(p.prefill))
+	data["prefill"] = prefill
+	for i, tmpl := range p.prefill {
+		pf := p.Config.Prefill[i]
+		prefill[pf.As] = p.runPrefill(ctx, pf.Tool, tmpl, data)
+	}
+
+	result := &mcp.GetPromptResult{
+		Description: p.Config.Description,
+		Messages:    make([]*mcp.PromptMessage, 0, len(p.messages)),
+	}
+	for i, tmpl := range p.messages {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %w", p.Config.Name, err)
+		}
+		result.Messages = append(result.Messages, &mcp.PromptMessage{
+			Role:    mcp.Role(p.Config.Messages[i].Role),
+			Content: &mcp.TextContent{Text: sb.String()},
+		})
+	}
+	return result, nil
+}
+
+// arguments 校验客户端传入的参数并按声明的类型转换，缺省的参数使用默认值
+func (p *PromptTemplate) arguments(raw map[string]string) (map[string]any, error) {
+	data := make(map[string]any, len(p.Config.Arguments)+1)
+	for _, a := range p.Config.Arguments {
+		value, ok := raw[a.Name]
+		if !ok || value == "" {
+			if a.Default == "" {
+				if a.Required {
+					return nil, fmt.Errorf("missing required argument %q", a.Name)
+				}
+				data[a.Name] = ""
+				continue
+			}
+			value = a.Default
+		}
+
+		v, err := convertArgument(a, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %q: %w", a.Name, err)
+		}
+		data[a.Name] = v
+	}
+	return data, nil
+}
+
+func convertArgument(a config.PromptArgumentConfig, value string) (any, error) {
+	switch a.Type {
+	case "integer":
+		return strconv.Atoi(value)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, fmt.Errorf("expected yyyy-MM-dd, got %q", value)
+		}
+		return value, nil
+	case "enum":
+		if !slices.Contains(a.Enum, value) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(a.Enum, ", "))
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// runPrefill 渲染工具入参并调用工具；失败不会中断提示词渲染，错误信息会作为结果文本
+func (p *PromptTemplate) runPrefill(ctx context.Context, tool string, tmpl *template.Template, data map[string]any) PrefillResult {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		log.Warn("提示词预填充参数渲染失败", "prompt", p.Config.Name, "tool", tool, "error", err)
+		return PrefillResult{Text: err.Error(), IsError: true}
+	}
+	argsJSON := strings.TrimSpace(sb.String())
+	if argsJSON == "" {
+		argsJSON = "{}"
+	}
+
+	res, err := p.callTool(ctx, tool, []byte(argsJSON))
+	if err != nil {
+		log.Warn("提示词预填充调用工具失败", "prompt", p.Config.Name, "tool", tool, "error", err)
+		return PrefillResult{Text: err.Error(), IsError: true}
+	}
+
+	out := PrefillResult{IsError: res.IsError}
+	var texts []string
+	for _, c := range res.Content {
+		if t, ok := c.(*mcp.TextContent); ok {
+			texts = append(texts, t.Text)
+		}
+	}
+	out.Text = strings.Join(texts, "\n")
+	if res.StructuredContent != nil {
+		// 统一转换为通用的 JSON 值，便于在模板中按字段访问
+		if b, err := json.Marshal(res.StructuredContent); err == nil {
+			json.Unmarshal(b, &out.Data)
+		}
+	}
+	return out
+}
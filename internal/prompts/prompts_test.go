@@ -0,0 +1,131 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"mcp/config"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newTestPrompt 创建带各类型参数的提示词，callTool 为 nil 时不做预填充
+func newTestPrompt(t *testing.T, callTool ToolCaller) *PromptTemplate {
+	t.Helper()
+	cfg := config.PromptTemplateConfig{
+		Name: "review",
+		Arguments: []config.PromptArgumentConfig{
+			{Name: "topic", Type: "string", Required: true},
+			{Name: "days", Type: "integer", Default: "7"},
+			{Name: "weight", Type: "number"},
+			{Name: "verbose", Type: "boolean"},
+			{Name: "since", Type: "date"},
+			{Name: "mood", Type: "enum", Enum: []string{"happy", "sad"}},
+		},
+		Messages: []config.PromptMessageConfig{{Role: "user", Text: "{{.topic}} {{.days}}"}},
+	}
+	if callTool != nil {
+		cfg.Prefill = []config.PromptPrefillConfig{{Tool: "diarySearch", As: "diary", Arguments: `{"query": {{json .topic}}, "days": {{json .days}}}`}}
+	}
+	p, err := NewPromptTemplate(cfg, callTool)
+	if err != nil {
+		t.Fatalf("NewPromptTemplate: %v", err)
+	}
+	return p
+}
+
+func TestPromptArguments(t *testing.T) {
+	p := newTestPrompt(t, nil)
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "defaults and optional arguments",
+			raw:  map[string]string{"topic": "work"},
+			want: map[string]any{"topic": "work", "days": 7, "weight": "", "verbose": "", "since": "", "mood": ""},
+		},
+		{
+			name: "every type converted",
+			raw:  map[string]string{"topic": "work", "days": "30", "weight": "0.5", "verbose": "true", "since": "2024-02-29", "mood": "sad"},
+			want: map[string]any{"topic": "work", "days": 30, "weight": 0.5, "verbose": true, "since": "2024-02-29", "mood": "sad"},
+		},
+		{
+			name: "empty value falls back to the default",
+			raw:  map[string]string{"topic": "work", "days": ""},
+			want: map[string]any{"topic": "work", "days": 7, "weight": "", "verbose": "", "since": "", "mood": ""},
+		},
+		{name: "missing required argument", raw: map[string]string{"days": "3"}, wantErr: `missing required argument "topic"`},
+		{name: "empty required argument", raw: map[string]string{"topic": ""}, wantErr: `missing required argument "topic"`},
+		{name: "bad integer", raw: map[string]string{"topic": "work", "days": "a week"}, wantErr: `invalid argument "days"`},
+		{name: "fractional integer", raw: map[string]string{"topic": "work", "days": "1.5"}, wantErr: `invalid argument "days"`},
+		{name: "bad number", raw: map[string]string{"topic": "work", "weight": "heavy"}, wantErr: `invalid argument "weight"`},
+		{name: "bad boolean", raw: map[string]string{"topic": "work", "verbose": "yes"}, wantErr: `invalid argument "verbose"`},
+		{name: "bad date format", raw: map[string]string{"topic": "work", "since": "2024/01/02"}, wantErr: "expected yyyy-MM-dd"},
+		{name: "impossible date", raw: map[string]string{"topic": "work", "since": "2023-02-29"}, wantErr: `invalid argument "since"`},
+		{name: "value outside the enum", raw: map[string]string{"topic": "work", "mood": "angry"}, wantErr: "must be one of happy, sad"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.arguments(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("arguments: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("arguments = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptHandleRejectsInvalidArguments(t *testing.T) {
+	called := false
+	p := newTestPrompt(t, func(context.Context, string, []byte) (*mcp.CallToolResult, error) {
+		called = true
+		return &mcp.CallToolResult{}, nil
+	})
+	req := &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Name: "review", Arguments: map[string]string{"topic": "work", "days": "x"}}}
+
+	_, err := p.Handle(context.Background(), req)
+	var wireErr *jsonrpc.Error
+	if !errors.As(err, &wireErr) || wireErr.Code != jsonrpc.CodeInvalidParams {
+		t.Fatalf("err = %v, want code %d", err, jsonrpc.CodeInvalidParams)
+	}
+	// 参数校验失败时不执行预填充
+	if called {
+		t.Fatal("prefill tool was called")
+	}
+}
+
+func TestPromptPrefillReceivesTypedArguments(t *testing.T) {
+	var argsJSON string
+	p := newTestPrompt(t, func(_ context.Context, name string, args []byte) (*mcp.CallToolResult, error) {
+		argsJSON = string(args)
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+	})
+	req := &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Name: "review", Arguments: map[string]string{"topic": "work"}}}
+
+	res, err := p.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	// 整数参数以 JSON 数字传给工具，默认值同样经过类型转换
+	if want := `{"query": "work", "days": 7}`; argsJSON != want {
+		t.Fatalf("prefill arguments = %s, want %s", argsJSON, want)
+	}
+	if text := res.Messages[0].Content.(*mcp.TextContent).Text; text != "work 7" {
+		t.Fatalf("message = %q", text)
+	}
+}
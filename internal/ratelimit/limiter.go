@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mcp/config"
+)
+
+// 触发限制的类型
+const (
+	LimitKey       = "rate"
+	LimitTool      = "tool_rate"
+	LimitQuota     = "daily_quota"
+	LimitToolQuota = "tool_daily_quota"
+)
+
+// ExceededError 表示调用方触发了限流或用完了每日配额
+type ExceededError struct {
+	// Limit 触发的限制类型
+	Limit string
+	// Tool 触发工具级限制时的工具名
+	Tool string
+	// RetryAfter 建议的重试等待时间
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	what := "rate limit exceeded"
+	switch e.Limit {
+	case LimitQuota:
+		what = "daily quota exceeded"
+	case LimitTool:
+		what = fmt.Sprintf("rate limit exceeded for tool %s", e.Tool)
+	case LimitToolQuota:
+		what = fmt.Sprintf("daily quota exceeded for tool %s", e.Tool)
+	}
+	return fmt.Sprintf("%s, retry after %s", what, e.RetryAfter.Round(time.Second))
+}
+
+// Limiter 根据配置对调用方进行限流
+type Limiter struct {
+	store Store
+	cfg   config.RateLimitConfig
+	tools map[string]config.ToolRateLimitConfig
+	now   func() time.Time
+}
+
+// NewLimiter 根据配置创建限流器
+func NewLimiter(cfg config.RateLimitConfig, store Store) *Limiter {
+	tools := make(map[string]config.ToolRateLimitConfig, len(cfg.Tools))
+	for _, t := range cfg.Tools {
+		tools[t.Tool] = t
+	}
+	return &Limiter{store: store, cfg: cfg, tools: tools, now: time.Now}
+}
+
+// Allow 检查调用方的一次请求是否在限制之内
+// tool 为空表示非工具调用，只检查调用方的令牌桶；超出限制时返回 *ExceededError。
+// 只有全部限制都通过时才计入：某项限制拒绝时，之前已取出的令牌与已增加的配额计数会被归还
+func (l *Limiter) Allow(ctx context.Context, caller, tool string) error {
+	now := l.now()
+	var undo []func()
+	rollback := func(err error) error {
+		// 归还失败只会让调用方多消耗一次额度，不影响本次的判定结果
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+		return err
+	}
+
+	if err := l.take(ctx, "key:"+caller, l.cfg.PerKey, now, &ExceededError{Limit: LimitKey}, &undo); err != nil {
+		return err
+	}
+	if tool == "" {
+		return nil
+	}
+
+	t, hasTool := l.tools[tool]
+	if hasTool {
+		if err := l.take(ctx, "tool:"+tool+":"+caller, t.BucketConfig, now, &ExceededError{Limit: LimitTool, Tool: tool}, &undo); err != nil {
+			return rollback(err)
+		}
+	}
+
+	// 每日配额按服务器本地时间的自然日计算
+	y, m, d := now.Date()
+	resetAt := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+	day := now.Format("20060102")
+	if hasTool && t.DailyQuota > 0 {
+		if err := l.incr(ctx, "quota:"+tool+":"+caller+":"+day, t.DailyQuota, resetAt, now, &ExceededError{Limit: LimitToolQuota, Tool: tool}, &undo); err != nil {
+			return rollback(err)
+		}
+	}
+	if l.cfg.DailyQuota > 0 {
+		if err := l.incr(ctx, "quota:"+caller+":"+day, l.cfg.DailyQuota, resetAt, now, &ExceededError{Limit: LimitQuota}, &undo); err != nil {
+			return rollback(err)
+		}
+	}
+	return nil
+}
+
+// take 从令牌桶中取出一个令牌，成功时将归还操作追加到 undo
+func (l *Limiter) take(ctx context.Context, key string, b config.BucketConfig, now time.Time, exceeded *ExceededError, undo *[]func()) error {
+	if b.Rate <= 0 {
+		return nil
+	}
+	ok, wait, err := l.store.Take(ctx, key, b, now)
+	if err != nil {
+		return fmt.Errorf("限流存储不可用: %w", err)
+	}
+	if !ok {
+		exceeded.RetryAfter = wait
+		return exceeded
+	}
+	*undo = append(*undo, func() { l.store.Refund(ctx, key, b, now) })
+	return nil
+}
+
+// incr 增加配额计数，成功时将撤销操作追加到 undo
+func (l *Limiter) incr(ctx context.Context, key string, limit int64, resetAt, now time.Time, exceeded *ExceededError, undo *[]func()) error {
+	ok, err := l.store.Incr(ctx, key, limit, resetAt, now)
+	if err != nil {
+		return fmt.Errorf("限流存储不可用: %w", err)
+	}
+	if !ok {
+		exceeded.RetryAfter = resetAt.Sub(now)
+		return exceeded
+	}
+	*undo = append(*undo, func() { l.store.Decr(ctx, key) })
+	return nil
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mcp/config"
+)
+
+// newTestLimiter 创建使用可控时钟与内存存储的限流器
+func newTestLimiter(cfg config.RateLimitConfig, now *time.Time) *Limiter {
+	l := NewLimiter(cfg, NewMemoryStore())
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+// wantLimit 断言 err 是触发了 limit 的 *ExceededError，limit 为空表示放行
+func wantLimit(t *testing.T, err error, limit string) {
+	t.Helper()
+	var exceeded *ExceededError
+	switch {
+	case limit == "" && err != nil:
+		t.Fatalf("Allow: %v, want allowed", err)
+	case limit != "" && (!errors.As(err, &exceeded) || exceeded.Limit != limit):
+		t.Fatalf("Allow err = %v, want %s", err, limit)
+	}
+}
+
+func TestLimiterPerKey(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	l := newTestLimiter(config.RateLimitConfig{PerKey: config.BucketConfig{Rate: 1, Burst: 2}}, &now)
+	ctx := context.Background()
+
+	wantLimit(t, l.Allow(ctx, "alice", ""), "")
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), "")
+	err := l.Allow(ctx, "alice", "")
+	wantLimit(t, err, LimitKey)
+	var exceeded *ExceededError
+	errors.As(err, &exceeded)
+	if exceeded.RetryAfter != time.Second {
+		t.Fatalf("RetryAfter = %s, want 1s", exceeded.RetryAfter)
+	}
+
+	// 调用方之间互不影响
+	wantLimit(t, l.Allow(ctx, "bob", ""), "")
+
+	now = now.Add(time.Second)
+	wantLimit(t, l.Allow(ctx, "alice", ""), "")
+}
+
+func TestLimiterToolQuotas(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	l := newTestLimiter(config.RateLimitConfig{
+		PerKey: config.BucketConfig{Rate: 100, Burst: 100},
+		Tools: []config.ToolRateLimitConfig{
+			{Tool: "web_search", BucketConfig: config.BucketConfig{Rate: 1, Burst: 1}, DailyQuota: 2},
+		},
+	}, &now)
+	ctx := context.Background()
+
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), "")
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), LimitTool)
+	// 其它工具与其它调用方不受影响
+	wantLimit(t, l.Allow(ctx, "alice", "diarySearch"), "")
+	wantLimit(t, l.Allow(ctx, "bob", "web_search"), "")
+
+	now = now.Add(time.Second)
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), "")
+	now = now.Add(time.Second)
+	err := l.Allow(ctx, "alice", "web_search")
+	wantLimit(t, err, LimitToolQuota)
+	var exceeded *ExceededError
+	errors.As(err, &exceeded)
+	if want := 12*time.Hour - 2*time.Second; exceeded.RetryAfter != want || exceeded.Tool != "web_search" {
+		t.Fatalf("exceeded = %+v, want tool web_search retry after %s", exceeded, want)
+	}
+
+	// 每日配额在次日零点重置
+	now = time.Date(2026, 1, 2, 0, 0, 0, 0, time.Local)
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), "")
+}
+
+func TestLimiterDailyQuota(t *testing.T) {
+	now := time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local)
+	l := newTestLimiter(config.RateLimitConfig{DailyQuota: 1}, &now)
+	ctx := context.Background()
+
+	// 非工具调用不消耗每日配额
+	wantLimit(t, l.Allow(ctx, "alice", ""), "")
+	wantLimit(t, l.Allow(ctx, "alice", "diarySearch"), "")
+	wantLimit(t, l.Allow(ctx, "alice", "memorySearch"), LimitQuota)
+	wantLimit(t, l.Allow(ctx, "alice", ""), "")
+}
+
+func TestLimiterRollsBackOnRejection(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	l := newTestLimiter(config.RateLimitConfig{
+		PerKey:     config.BucketConfig{Rate: 0.001, Burst: 2},
+		DailyQuota: 1,
+		Tools: []config.ToolRateLimitConfig{
+			{Tool: "web_search", BucketConfig: config.BucketConfig{Rate: 0.001, Burst: 3}, DailyQuota: 2},
+		},
+	}, &now)
+	ctx := context.Background()
+
+	wantLimit(t, l.Allow(ctx, "alice", "diarySearch"), "")
+	// 总配额已用完：已取出的调用方与工具令牌、已增加的工具配额全部归还
+	for i := 0; i < 3; i++ {
+		wantLimit(t, l.Allow(ctx, "alice", "web_search"), LimitQuota)
+	}
+
+	// 调用方令牌桶中仍有一个令牌
+	wantLimit(t, l.Allow(ctx, "alice", ""), "")
+	wantLimit(t, l.Allow(ctx, "alice", ""), LimitKey)
+
+	// 工具令牌桶仍是满的，工具配额也没有被消耗：只受工具限制时可调用两次，第三次被工具配额拒绝
+	l.cfg.DailyQuota = 0
+	l.cfg.PerKey = config.BucketConfig{}
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), "")
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), "")
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), LimitToolQuota)
+	// 被工具配额拒绝的调用归还了工具令牌
+	l.tools["web_search"] = config.ToolRateLimitConfig{Tool: "web_search", BucketConfig: config.BucketConfig{Rate: 0.001, Burst: 3}}
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), "")
+	wantLimit(t, l.Allow(ctx, "alice", "web_search"), LimitTool)
+}
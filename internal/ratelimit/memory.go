@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"mcp/config"
+)
+
+// sweepInterval 清理过期条目的最小间隔
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full 令牌补满的时间，此后该条目与新建的桶等价，可以清理
+	full time.Time
+}
+
+type counter struct {
+	n       int64
+	resetAt time.Time
+}
+
+// MemoryStore 是进程内的 Store 实现
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	counters  map[string]*counter
+	lastSweep time.Time
+}
+
+// NewMemoryStore 创建进程内存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucket),
+		counters: make(map[string]*counter),
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, b config.BucketConfig, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	burst := float64(b.Burst)
+	bk, ok := s.buckets[key]
+	if !ok {
+		bk = &bucket{tokens: burst, last: now}
+		s.buckets[key] = bk
+	}
+	if elapsed := now.Sub(bk.last).Seconds(); elapsed > 0 {
+		bk.tokens = min(burst, bk.tokens+elapsed*b.Rate)
+		bk.last = now
+	}
+	if bk.tokens < 1 {
+		wait := time.Duration((1 - bk.tokens) / b.Rate * float64(time.Second))
+		return false, wait, nil
+	}
+	bk.tokens--
+	bk.full = now.Add(time.Duration((burst - bk.tokens) / b.Rate * float64(time.Second)))
+	return true, 0, nil
+}
+
+func (s *MemoryStore) Incr(ctx context.Context, key string, limit int64, resetAt time.Time, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.resetAt) {
+		c = &counter{resetAt: resetAt}
+		s.counters[key] = c
+	}
+	if c.n >= limit {
+		return false, nil
+	}
+	c.n++
+	return true, nil
+}
+
+func (s *MemoryStore) Refund(ctx context.Context, key string, b config.BucketConfig, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 桶已被清理时等同于已补满
+	bk, ok := s.buckets[key]
+	if !ok {
+		return nil
+	}
+	burst := float64(b.Burst)
+	bk.tokens = min(burst, bk.tokens+1)
+	bk.full = bk.last.Add(time.Duration((burst - bk.tokens) / b.Rate * float64(time.Second)))
+	return nil
+}
+
+func (s *MemoryStore) Decr(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.counters[key]; ok && c.n > 0 {
+		c.n--
+	}
+	return nil
+}
+
+// sweep 删除已补满的令牌桶与已过期的计数器，避免按调用方建立的条目无限增长
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for k, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, k)
+		}
+	}
+	for k, c := range s.counters {
+		if !now.Before(c.resetAt) {
+			delete(s.counters, k)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcp/config"
+)
+
+func TestMemoryStoreBucketRefill(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	b := config.BucketConfig{Rate: 2, Burst: 3}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+
+	// 新建的桶是满的，可以连续取出 burst 个令牌
+	for i := 0; i < 3; i++ {
+		if ok, _, err := s.Take(ctx, "k", b, now); !ok || err != nil {
+			t.Fatalf("take %d: ok=%v err=%v", i, ok, err)
+		}
+	}
+	ok, wait, err := s.Take(ctx, "k", b, now)
+	if ok || err != nil {
+		t.Fatalf("take from empty bucket: ok=%v err=%v", ok, err)
+	}
+	if wait != 500*time.Millisecond {
+		t.Fatalf("wait = %s, want 500ms", wait)
+	}
+
+	// 按 rate 补充令牌
+	now = now.Add(500 * time.Millisecond)
+	if ok, _, _ := s.Take(ctx, "k", b, now); !ok {
+		t.Fatal("take after refill failed")
+	}
+	if ok, _, _ := s.Take(ctx, "k", b, now); ok {
+		t.Fatal("take succeeded beyond the refilled tokens")
+	}
+
+	// 补充的令牌不超过 burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := s.Take(ctx, "k", b, now); !ok {
+			t.Fatalf("take %d after a long idle period failed", i)
+		}
+	}
+	if ok, _, _ := s.Take(ctx, "k", b, now); ok {
+		t.Fatal("bucket refilled beyond burst")
+	}
+
+	// 不同的桶互不影响
+	if ok, _, _ := s.Take(ctx, "other", b, now); !ok {
+		t.Fatal("take from another bucket failed")
+	}
+}
+
+func TestMemoryStoreRefund(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	b := config.BucketConfig{Rate: 1, Burst: 1}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+
+	s.Take(ctx, "k", b, now)
+	if ok, _, _ := s.Take(ctx, "k", b, now); ok {
+		t.Fatal("bucket should be empty")
+	}
+	s.Refund(ctx, "k", b, now)
+	if ok, _, _ := s.Take(ctx, "k", b, now); !ok {
+		t.Fatal("take after refund failed")
+	}
+
+	// 归还不会使令牌数超过 burst
+	s.Refund(ctx, "k", b, now)
+	s.Refund(ctx, "k", b, now)
+	s.Take(ctx, "k", b, now)
+	if ok, _, _ := s.Take(ctx, "k", b, now); ok {
+		t.Fatal("refund filled the bucket beyond burst")
+	}
+}
+
+func TestMemoryStoreCounter(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	resetAt := now.Add(12 * time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if ok, err := s.Incr(ctx, "c", 2, resetAt, now); !ok || err != nil {
+			t.Fatalf("incr %d: ok=%v err=%v", i, ok, err)
+		}
+	}
+	if ok, _ := s.Incr(ctx, "c", 2, resetAt, now); ok {
+		t.Fatal("incr beyond limit succeeded")
+	}
+
+	s.Decr(ctx, "c")
+	if ok, _ := s.Incr(ctx, "c", 2, resetAt, now); !ok {
+		t.Fatal("incr after decr failed")
+	}
+
+	// 到达 resetAt 后重新计数
+	if ok, _ := s.Incr(ctx, "c", 2, resetAt.Add(12*time.Hour), resetAt); !ok {
+		t.Fatal("incr after reset failed")
+	}
+}
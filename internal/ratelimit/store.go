@@ -0,0 +1,22 @@
+// Package ratelimit 按调用方与工具进行令牌桶限流并统计每日配额
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"mcp/config"
+)
+
+// Store 保存令牌桶与计数器的状态
+// 内置的 MemoryStore 只在单个实例内生效，多实例部署时可基于 Redis 等共享存储实现该接口
+type Store interface {
+	// Take 从 bucket 中取出一个令牌；令牌不足时返回 false 以及补足一个令牌所需的等待时间
+	Take(ctx context.Context, bucket string, b config.BucketConfig, now time.Time) (bool, time.Duration, error)
+	// Incr 将 counter 的计数加一，计数在 resetAt 时清零；计数已达到 limit 时不再增加并返回 false
+	Incr(ctx context.Context, counter string, limit int64, resetAt time.Time, now time.Time) (bool, error)
+	// Refund 归还一个由 Take 取出的令牌，令牌数不超过 b.Burst
+	Refund(ctx context.Context, bucket string, b config.BucketConfig, now time.Time) error
+	// Decr 撤销一次 Incr，计数不小于 0
+	Decr(ctx context.Context, counter string) error
+}
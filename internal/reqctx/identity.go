@@ -0,0 +1,66 @@
+package reqctx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// RequestIDHeader 是双向携带请求 ID 的请求头
+const RequestIDHeader = "X-Request-Id"
+
+// Identity 是一条 MCP 消息的调用方身份，取自承载该消息的 HTTP 请求
+// MCP 会话的 context 来自 initialize 请求，后续请求的身份只能随消息本身（RequestExtra.TokenInfo）传递
+type Identity struct {
+	APIKey    string
+	Principal *Principal
+	RemoteIP  string
+}
+
+// tokenInfoIdentityKey 是 Identity 在 TokenInfo.Extra 中的键
+const tokenInfoIdentityKey = "mcp.identity"
+
+// tokenInfoLifetime 是 TokenInfo 的有效期，SDK 要求其非零且未过期；TokenInfo 只在当前 HTTP 请求内使用
+const tokenInfoLifetime = time.Minute
+
+// TokenInfo 将 info 中的调用方身份转换为 SDK 的 TokenInfo
+// UserID 为会话绑定的调用方（见 SessionUser），SDK 据此拒绝其他调用方复用该会话
+func TokenInfo(info *Info) *auth.TokenInfo {
+	ti := &auth.TokenInfo{
+		UserID:     SessionUser(info),
+		Expiration: time.Now().Add(tokenInfoLifetime),
+		Extra: map[string]any{tokenInfoIdentityKey: Identity{
+			APIKey:    info.APIKey,
+			Principal: info.Principal,
+			RemoteIP:  info.RemoteIP,
+		}},
+	}
+	if info.Principal != nil {
+		ti.Scopes = info.Principal.Scopes
+	}
+	return ti
+}
+
+// IdentityFromTokenInfo 取出 TokenInfo 携带的调用方身份
+func IdentityFromTokenInfo(ti *auth.TokenInfo) (Identity, bool) {
+	if ti == nil {
+		return Identity{}, false
+	}
+	id, ok := ti.Extra[tokenInfoIdentityKey].(Identity)
+	return id, ok
+}
+
+// SessionUser 返回 MCP 会话所绑定的调用方：认证后的用户 ID，没有用户 ID 时为凭据摘要；
+// 既没有身份也没有凭据时返回空字符串，会话不做绑定
+func SessionUser(info *Info) string {
+	if info.Principal != nil && info.Principal.UserID != "" {
+		return "user:" + info.Principal.UserID
+	}
+	if info.APIKey != "" {
+		sum := sha256.Sum256([]byte(info.APIKey))
+		return "key:" + hex.EncodeToString(sum[:])
+	}
+	return ""
+}
@@ -0,0 +1,16 @@
+package reqctx
+
+import "slices"
+
+// Principal 是通过认证的调用方身份
+type Principal struct {
+	// UserID 调用方所属的用户
+	UserID string `json:"userId"`
+	// Scopes 授予调用方的权限范围
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// HasScope 判断调用方是否拥有指定的权限范围
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
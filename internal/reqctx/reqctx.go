@@ -0,0 +1,93 @@
+// Package reqctx 定义随请求在 HTTP 层、MCP 处理函数、工具与 gRPC 调用之间传递的上下文信息
+package reqctx
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 传输方式
+const (
+	TransportStreamable = "streamable"
+	TransportSSE        = "sse"
+)
+
+// ClientInfo 是客户端在 initialize 时上报的名称与版本
+type ClientInfo struct {
+	Name    string
+	Version string
+}
+
+// Info 是一次请求的上下文信息
+type Info struct {
+	// RequestID 请求 ID，取自当前消息所在 HTTP 请求的 X-Request-Id 请求头或由服务端生成；
+	// 同一 MCP 会话中的每个请求各不相同
+	RequestID string
+	// APIKey 调用方携带的 API Key，仅在未开启认证时透传给后端（见 BackendAPIKey）；OAuth 模式下访问令牌不会写入
+	APIKey string
+	// Principal 通过认证的调用方身份，未开启认证时为 nil
+	Principal *Principal
+	// RemoteIP 客户端 IP
+	RemoteIP string
+	// Transport 请求所使用的传输方式
+	Transport string
+	// SessionID MCP 会话 ID
+	SessionID string
+	// Client 客户端信息，会话初始化后可用
+	Client ClientInfo
+	// Tool 正在调用的工具名称，仅 tools/call 请求有值
+	Tool string
+	// Deadline 本次调用的截止时间，受 server.request_timeout 限制，零值表示没有截止时间
+	Deadline time.Time
+	// TraceParent 与 TraceState 是 W3C Trace Context，调用方未携带时由服务端生成 traceparent
+	TraceParent string
+	TraceState  string
+}
+
+type infoKey struct{}
+
+// With 返回携带 info 的 context
+func With(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// From 返回 context 中的请求信息，不存在时返回零值，调用方无需判空
+func From(ctx context.Context) *Info {
+	if info, ok := ctx.Value(infoKey{}).(*Info); ok {
+		return info
+	}
+	return &Info{}
+}
+
+// Clone 返回 info 的浅拷贝，用于在派生的 context 中修改字段
+func (i *Info) Clone() *Info {
+	c := *i
+	return &c
+}
+
+// NewRequestID 生成新的请求 ID
+func NewRequestID() string {
+	return uuid.NewString()
+}
+
+// BackendAPIKey 返回需要转发给后端的 API Key，用于请求消息的 api_key 字段与 x-api-key metadata
+// 调用方已通过认证时后端只收到校验后的身份（x-user-id 与 x-user-scopes），不再收到其原始凭据，返回空字符串
+func BackendAPIKey(ctx context.Context) string {
+	info := From(ctx)
+	if info.Principal != nil {
+		return ""
+	}
+	return info.APIKey
+}
+
+// RequestID 返回请求 ID
+func RequestID(ctx context.Context) string {
+	return From(ctx).RequestID
+}
+
+// SessionID 返回 MCP 会话 ID
+func SessionID(ctx context.Context) string {
+	return From(ctx).SessionID
+}
@@ -0,0 +1,46 @@
+package reqctx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// W3C Trace Context 请求头
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+var traceParentPattern = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// ValidTraceParent 判断 traceparent 是否符合 W3C Trace Context 格式，全零的 trace-id 与 parent-id 视为无效
+func ValidTraceParent(tp string) bool {
+	if !traceParentPattern.MatchString(tp) || strings.HasPrefix(tp, "ff-") {
+		return false
+	}
+	parts := strings.Split(tp, "-")
+	return strings.Trim(parts[1], "0") != "" && strings.Trim(parts[2], "0") != ""
+}
+
+// NewTraceParent 生成一个新的根 traceparent（已采样）
+func NewTraceParent() string {
+	return "00-" + randomHex(16) + "-" + randomHex(8) + "-01"
+}
+
+// ChildTraceParent 返回同一 trace 下的新 span，用于向下游发起调用
+// tp 无效时返回新的根 traceparent
+func ChildTraceParent(tp string) string {
+	if !ValidTraceParent(tp) {
+		return NewTraceParent()
+	}
+	parts := strings.Split(tp, "-")
+	return "00-" + parts[1] + "-" + randomHex(8) + "-" + parts[3]
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
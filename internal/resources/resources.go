@@ -0,0 +1,191 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"mcp/internal/grpc"
+	"mcp/internal/reqctx"
+	pb "mcp/proto"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Resource 是一类由后端提供、以 URI 模板寻址的 MCP 资源
+type Resource interface {
+	// GetTemplateDef 返回该类资源在 MCP 中注册的 URI 模板
+	GetTemplateDef() *mcp.ResourceTemplate
+	// Read 读取单个资源，资源不存在时返回 mcp.ResourceNotFoundError
+	Read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error)
+	// List 列出当前用户最近的资源
+	List(ctx context.Context, maxResults int32) ([]*mcp.Resource, error)
+	// Tool 返回与该类资源对应的工具名称，列出、读取与订阅资源所需的权限范围与该工具相同
+	Tool() string
+}
+
+// resourceID 从形如 scheme://{id} 的 URI 中解析出资源 ID
+func resourceID(uri, scheme string) (string, bool) {
+	raw, ok := strings.CutPrefix(uri, scheme+"://")
+	if !ok || raw == "" {
+		return "", false
+	}
+	id, err := url.PathUnescape(raw)
+	if err != nil {
+		return "", false
+	}
+	return id, true
+}
+
+func resourceURI(scheme, id string) string {
+	return scheme + "://" + url.PathEscape(id)
+}
+
+// DiaryResource 将用户的日记暴露为 diary://{diaryId} 资源
+type DiaryResource struct{}
+
+// NewDiaryResource 创建一个新的 DiaryResource 实例
+func NewDiaryResource() *DiaryResource {
+	return &DiaryResource{}
+}
+
+// GetTemplateDef 返回日记资源的 URI 模板
+func (r *DiaryResource) GetTemplateDef() *mcp.ResourceTemplate {
+	return &mcp.ResourceTemplate{
+		Name:        "diary",
+		Title:       "日记",
+		Description: "用户的单篇日记，diaryId 可通过 diarySearch 工具或 resources/list 获得",
+		URITemplate: "diary://{diaryId}",
+		MIMEType:    "text/plain",
+	}
+}
+
+// Tool 返回对应的工具 diarySearch
+func (r *DiaryResource) Tool() string {
+	return "diarySearch"
+}
+
+// Read 调用后端 GetDiary 读取单篇日记
+func (r *DiaryResource) Read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	id, ok := resourceID(uri, "diary")
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	apiKey := reqctx.BackendAPIKey(ctx)
+	res, err := grpc.GetDiary(ctx, &pb.GetDiaryRequest{ApiKey: apiKey, DiaryId: id})
+	if err != nil {
+		return nil, err
+	}
+	if res.Diary == nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	d := res.Diary
+	text := fmt.Sprintf("Diary [%s] (ID: %s, Emotion: %s):\n%s\n", d.Date, d.DiaryId, d.Emotion, d.Content)
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: "text/plain", Text: text}},
+	}, nil
+}
+
+// List 调用后端 ListDiaries 列出最近的日记
+func (r *DiaryResource) List(ctx context.Context, maxResults int32) ([]*mcp.Resource, error) {
+	apiKey := reqctx.BackendAPIKey(ctx)
+	res, err := grpc.ListDiaries(ctx, &pb.ListDiariesRequest{ApiKey: apiKey, MaxResults: maxResults})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*mcp.Resource, 0, len(res.Diaries))
+	for _, d := range res.Diaries {
+		list = append(list, &mcp.Resource{
+			URI:         resourceURI("diary", d.DiaryId),
+			Name:        "diary-" + d.DiaryId,
+			Title:       fmt.Sprintf("日记 %s", d.Date),
+			Description: summary(d.Content),
+			MIMEType:    "text/plain",
+		})
+	}
+	return list, nil
+}
+
+// MemoryResource 将用户的记忆暴露为 memory://{sourceId} 资源
+type MemoryResource struct{}
+
+// NewMemoryResource 创建一个新的 MemoryResource 实例
+func NewMemoryResource() *MemoryResource {
+	return &MemoryResource{}
+}
+
+// GetTemplateDef 返回记忆资源的 URI 模板
+func (r *MemoryResource) GetTemplateDef() *mcp.ResourceTemplate {
+	return &mcp.ResourceTemplate{
+		Name:        "memory",
+		Title:       "记忆",
+		Description: "用户的单条记忆，sourceId 可通过 memorySearch 工具或 resources/list 获得",
+		URITemplate: "memory://{sourceId}",
+		MIMEType:    "text/plain",
+	}
+}
+
+// Tool 返回对应的工具 memorySearch
+func (r *MemoryResource) Tool() string {
+	return "memorySearch"
+}
+
+// Read 调用后端 GetMemory 读取单条记忆
+func (r *MemoryResource) Read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	id, ok := resourceID(uri, "memory")
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	apiKey := reqctx.BackendAPIKey(ctx)
+	res, err := grpc.GetMemory(ctx, &pb.GetMemoryRequest{ApiKey: apiKey, SourceId: id})
+	if err != nil {
+		return nil, err
+	}
+	if res.Memory == nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	m := res.Memory
+	text := fmt.Sprintf("[%s] (Source: %s, Time: %s):\n%s\n", m.Type, m.SourceId, m.CreatedAt, m.Content)
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: "text/plain", Text: text}},
+	}, nil
+}
+
+// List 调用后端 ListMemories 列出最近的记忆
+func (r *MemoryResource) List(ctx context.Context, maxResults int32) ([]*mcp.Resource, error) {
+	apiKey := reqctx.BackendAPIKey(ctx)
+	res, err := grpc.ListMemories(ctx, &pb.ListMemoriesRequest{ApiKey: apiKey, MaxResults: maxResults})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*mcp.Resource, 0, len(res.Memories))
+	for _, m := range res.Memories {
+		list = append(list, &mcp.Resource{
+			URI:         resourceURI("memory", m.SourceId),
+			Name:        "memory-" + m.SourceId,
+			Title:       fmt.Sprintf("%s %s", m.Type, m.CreatedAt),
+			Description: summary(m.Content),
+			MIMEType:    "text/plain",
+		})
+	}
+	return list, nil
+}
+
+// summary 截取内容开头作为资源描述
+func summary(content string) string {
+	const maxRunes = 80
+	runes := []rune(strings.TrimSpace(content))
+	if len(runes) <= maxRunes {
+		return string(runes)
+	}
+	return string(runes[:maxRunes]) + "…"
+}
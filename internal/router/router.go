@@ -2,11 +2,14 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
 
 	mcp_impl "mcp"
 	"mcp/config"
+	"mcp/internal/auth"
 	"mcp/internal/handler"
 	"mcp/internal/middleware"
+	"mcp/pkg/log"
 )
 
 // NewRouter 构建带中间件与全部路由的Gin路由
@@ -16,33 +19,98 @@ func NewRouter(cfg *config.MCPConfig) *gin.Engine {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	router := gin.New()
+	var (
+		corsCfg        config.CORSConfig
+		trustedProxies []string
+	)
+	if cfg != nil {
+		corsCfg = cfg.CORS
+		trustedProxies = cfg.Server.TrustedProxies
+	}
+
+	router := newEngine(trustedProxies)
 
-	// 添加全局中间件: Recovery, CORS, Auth
+	// 添加全局中间件: Recovery, CORS, RequestContext
 	router.Use(gin.Recovery())
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORS(corsCfg))
 	
-	// 使用 Auth 中间件提取 API Key (不进行本地校验，仅透传)
-	router.Use(middleware.Auth(""))
+	// 生成请求 ID 并提取 API Key 写入请求上下文，MCP 端点的校验见 Setup
+	router.Use(middleware.RequestContext())
 
 	return router
 }
 
+// newEngine 创建 Gin 引擎，只信任 trustedProxies 转发的 X-Forwarded-For，为空时客户端 IP 取自连接的对端地址
+// gin 默认信任所有代理，任何调用方都可以伪造 X-Forwarded-For 绕过按 IP 的限流
+func newEngine(trustedProxies []string) *gin.Engine {
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Error("可信代理配置非法，不信任任何代理", "error", err)
+		r.SetTrustedProxies(nil)
+	}
+	return r
+}
+
+// SetupHealth 构建只提供 /health 的路由，用于 server.health_port 上的明文健康检查
+func SetupHealth(cfg *config.MCPConfig) *gin.Engine {
+	r := newEngine(cfg.Server.TrustedProxies)
+	r.Use(gin.Recovery())
+	r.GET("/health", handler.NewHealthHandler(cfg.Grpc.HealthCheck.ServiceName).Health)
+	return r
+}
+
 // Setup 为 MCP 服务器配置所有路由
-func Setup(cfg *config.MCPConfig, server *mcp_impl.MCPServer) *gin.Engine {
+// keyStore 不为 nil 时，MCP 端点要求有效的 API Key、OAuth 访问令牌或经过校验的客户端证书
+func Setup(cfg *config.MCPConfig, server *mcp_impl.MCPServer, keyStore auth.KeyStore) *gin.Engine {
 	r := NewRouter(cfg)
 
 	// 健康检查接口
-	r.GET("/health", handler.Health)
+	r.GET("/health", handler.NewHealthHandler(cfg.Grpc.HealthCheck.ServiceName).Health)
+	// Prometheus 格式的监控指标
+	r.GET("/metrics", handler.Metrics)
+
+	// OAuth 2.1 资源服务器模式：公布受保护资源元数据，供客户端发现授权服务器
+	var resourceMetadataURL string
+	if oauth := cfg.Auth.OAuth; oauth.Enabled {
+		metadata := gin.WrapH(sdkauth.ProtectedResourceMetadataHandler(auth.ProtectedResourceMetadata(oauth)))
+		for _, path := range auth.ResourceMetadataPaths(oauth.Resource) {
+			r.GET(path, metadata)
+		}
+		resourceMetadataURL = auth.ResourceMetadataURL(oauth.Resource)
+	}
+
+	// MCP 端点校验 Origin，防止 DNS 重绑定攻击
+	origin := middleware.ValidateOrigin(cfg.CORS)
+	// 排空期间不再建立新的会话
+	mcpRoutes := r.Group("", origin, middleware.RefuseNewSessions(server.Draining))
+	if tlsCfg := cfg.Server.TLS; tlsCfg.Enabled && tlsCfg.ClientAuth != "none" {
+		mcpRoutes.Use(middleware.ClientCert(auth.NewClientCertPrincipals(tlsCfg.ClientPrincipals)))
+	}
+	if keyStore != nil {
+		mcpRoutes.Use(middleware.Authenticate(keyStore, resourceMetadataURL))
+	}
 
 	// Streamable HTTP 通讯协议路由 (官方推荐)
 	mcpHandler := handler.NewMCPHandler(server)
-	r.POST("/mcp", mcpHandler.Handle)
+	mcpRoutes.POST("/mcp", mcpHandler.Handle)
+	mcpRoutes.GET("/mcp", mcpHandler.Handle)
+	mcpRoutes.DELETE("/mcp", mcpHandler.Handle)
 
 	// 传统 SSE 通讯协议路由 (为了向下兼容)
+	// /messages 与 /sse 使用相同的认证，调用方必须与建立 SSE 连接的调用方一致
 	sseHandler := handler.NewSSEHandler(server)
-	r.GET("/sse", sseHandler.Connect)
-	r.POST("/messages", sseHandler.Message)
+	mcpRoutes.GET("/sse", sseHandler.Connect)
+	mcpRoutes.POST("/messages", sseHandler.Message)
+
+	// 工具注册表管理接口，仅在配置了 admin.api_key 时开放
+	if cfg.Admin.APIKey != "" {
+		adminHandler := handler.NewAdminHandler(server)
+		admin := r.Group("/admin", middleware.Auth(cfg.Admin.APIKey))
+		admin.GET("/tools", adminHandler.ListTools)
+		admin.POST("/tools/:name/enable", adminHandler.EnableTool)
+		admin.POST("/tools/:name/disable", adminHandler.DisableTool)
+		admin.DELETE("/tools/:name", adminHandler.RemoveTool)
+	}
 
 	return r
 }
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"mcp/config"
+	"mcp/internal/reqctx"
+)
+
+func TestClientIPIgnoresUntrustedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name    string
+		proxies []string
+		remote  string
+		want    string
+	}{
+		{"no trusted proxies by default", nil, "203.0.113.7:40000", "203.0.113.7"},
+		{"request from a trusted proxy", []string{"10.0.0.0/8"}, "10.1.2.3:40000", "198.51.100.9"},
+		{"request from an untrusted address", []string{"10.0.0.0/8"}, "203.0.113.7:40000", "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.MCPConfig{Server: config.ServerConfig{TrustedProxies: tt.proxies}}
+			r := NewRouter(cfg)
+			r.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, reqctx.From(c.Request.Context()).RemoteIP)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", "198.51.100.9")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if got := w.Body.String(); got != tt.want {
+				t.Fatalf("client IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
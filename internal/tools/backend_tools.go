@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mcp/internal/grpc"
+	"mcp/internal/reqctx"
+	pb "mcp/proto"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// BackendTool 是由 Java 后端通过 ListTools 声明、通过 CallTool 执行的工具
+type BackendTool struct {
+	Def          *pb.ToolDefinition
+	inputSchema  map[string]interface{}
+	outputSchema map[string]interface{}
+}
+
+// NewBackendTool 根据后端返回的工具定义创建工具，Schema 非法时返回错误
+func NewBackendTool(def *pb.ToolDefinition) (*BackendTool, error) {
+	t := &BackendTool{Def: def}
+
+	if def.Name == "" {
+		return nil, fmt.Errorf("后端工具缺少名称")
+	}
+	if def.InputSchemaJson == "" {
+		t.inputSchema = map[string]interface{}{"type": "object"}
+	} else if err := json.Unmarshal([]byte(def.InputSchemaJson), &t.inputSchema); err != nil {
+		return nil, fmt.Errorf("后端工具 %s 的 input_schema_json 非法: %w", def.Name, err)
+	}
+	if t.inputSchema["type"] != "object" {
+		return nil, fmt.Errorf("后端工具 %s 的 input_schema_json 的 type 必须为 object", def.Name)
+	}
+	if def.OutputSchemaJson != "" {
+		if err := json.Unmarshal([]byte(def.OutputSchemaJson), &t.outputSchema); err != nil {
+			return nil, fmt.Errorf("后端工具 %s 的 output_schema_json 非法: %w", def.Name, err)
+		}
+	}
+
+	return t, nil
+}
+
+// GetToolDef 返回该工具在 MCP 中注册的定义
+func (t *BackendTool) GetToolDef() *mcp.Tool {
+	tool := &mcp.Tool{
+		Name:        t.Def.Name,
+		Description: t.Def.Description,
+		InputSchema: t.inputSchema,
+	}
+	if t.outputSchema != nil {
+		tool.OutputSchema = t.outputSchema
+	}
+	return tool
+}
+
+// Handle 将调用转发给后端的 CallTool RPC
+func (t *BackendTool) Handle(ctx context.Context, argsJSON json.RawMessage) (*mcp.CallToolResult, error) {
+	apiKey := reqctx.BackendAPIKey(ctx)
+
+	if len(argsJSON) == 0 {
+		argsJSON = json.RawMessage("{}")
+	}
+
+	res, err := grpc.CallTool(ctx, &pb.CallToolRequest{
+		ApiKey:        apiKey,
+		Name:          t.Def.Name,
+		ArgumentsJson: string(argsJSON),
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("gRPC error: %v", err)},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: res.Text}},
+		IsError: res.IsError,
+	}
+	if res.StructuredContentJson != "" && json.Valid([]byte(res.StructuredContentJson)) {
+		result.StructuredContent = json.RawMessage(res.StructuredContentJson)
+	}
+	return result, nil
+}
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+
+	"mcp/config"
+	"mcp/internal/grpc"
+	"mcp/pkg/log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DeclaredTool 是在 config.yaml 中声明的工具，调用时转发到绑定的 gRPC 方法或 HTTP 接口
+type DeclaredTool struct {
+	Config config.DeclaredToolConfig
+	schema map[string]interface{}
+	tmpl   *template.Template
+	client *http.Client
+
+	mu     sync.Mutex
+	method protoreflect.MethodDescriptor // 尚未解析时为 nil，见 grpcMethod
+}
+
+// templateFuncs 是响应模板中可用的辅助函数
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+}
+
+// NewDeclaredTool 根据配置创建一个声明式工具
+// grpc 绑定的方法描述符通过后端的 server reflection 解析，后端暂不可用时在首次调用时重新解析
+func NewDeclaredTool(ctx context.Context, cfg config.DeclaredToolConfig) (*DeclaredTool, error) {
+	t := &DeclaredTool{Config: cfg}
+
+	if err := json.Unmarshal([]byte(cfg.InputSchema), &t.schema); err != nil {
+		return nil, fmt.Errorf("工具 %s 的 input_schema 非法: %w", cfg.Name, err)
+	}
+
+	if cfg.ResponseTemplate != "" {
+		tmpl, err := template.New(cfg.Name).Funcs(templateFuncs).Parse(cfg.ResponseTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("工具 %s 的 response_template 非法: %w", cfg.Name, err)
+		}
+		t.tmpl = tmpl
+	}
+
+	switch cfg.Backend.Type {
+	case "grpc":
+		if _, err := t.grpcMethod(ctx); err != nil {
+			log.Warn("无法解析声明式工具绑定的 gRPC 方法，将在调用时重试", "tool", cfg.Name, "method", cfg.Backend.GrpcMethod, "error", err)
+		}
+	case "http":
+		t.client = &http.Client{Timeout: cfg.Backend.Timeout}
+	}
+
+	return t, nil
+}
+
+// GetToolDef 返回该工具在 MCP 中注册的定义
+func (t *DeclaredTool) GetToolDef() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        t.Config.Name,
+		Description: t.Config.Description,
+		InputSchema: t.schema,
+	}
+}
+
+// Handle 执行工具调用，入参为客户端传入的原始 JSON
+func (t *DeclaredTool) Handle(ctx context.Context, argsJSON json.RawMessage) (*mcp.CallToolResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.Config.Backend.Timeout)
+	defer cancel()
+
+	var (
+		body []byte
+		err  error
+	)
+	switch t.Config.Backend.Type {
+	case "grpc":
+		var md protoreflect.MethodDescriptor
+		if md, err = t.grpcMethod(ctx); err == nil {
+			body, err = grpc.InvokeJSON(ctx, md, argsJSON)
+		}
+	case "http":
+		body, err = t.callHTTP(ctx, argsJSON)
+	}
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("%s error: %v", t.Config.Backend.Type, err)},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return t.render(body)
+}
+
+// grpcMethod 返回绑定的 gRPC 方法描述符，尚未解析成功时重新解析
+func (t *DeclaredTool) grpcMethod(ctx context.Context) (protoreflect.MethodDescriptor, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.method != nil {
+		return t.method, nil
+	}
+	md, err := grpc.FindMethod(ctx, t.Config.Backend.GrpcMethod)
+	if err != nil {
+		return nil, err
+	}
+	t.method = md
+	return md, nil
+}
+
+// callHTTP 将参数转发到绑定的 HTTP 接口
+func (t *DeclaredTool) callHTTP(ctx context.Context, argsJSON json.RawMessage) ([]byte, error) {
+	b := t.Config.Backend
+	target := b.URL
+	var reqBody io.Reader
+
+	if b.HTTPMethod == http.MethodGet {
+		var args map[string]interface{}
+		if len(argsJSON) > 0 {
+			if err := json.Unmarshal(argsJSON, &args); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal args: %w", err)
+			}
+		}
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url: %w", err)
+		}
+		q := u.Query()
+		for k, v := range args {
+			q.Set(k, fmt.Sprintf("%v", v))
+		}
+		u.RawQuery = q.Encode()
+		target = u.String()
+	} else {
+		if len(argsJSON) == 0 {
+			argsJSON = json.RawMessage("{}")
+		}
+		reqBody = bytes.NewReader(argsJSON)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, b.HTTPMethod, target, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if reqBody != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	// 与 gRPC 调用相同的调用方身份与链路信息，配置的请求头优先
+	for k, v := range grpc.CallerMetadata(ctx) {
+		httpReq.Header.Set(k, v)
+	}
+	for k, v := range b.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// 响应体可能包含后端的内部信息，只记录在服务端日志中
+		log.Warn("声明式工具的 HTTP 后端返回错误", "tool", t.Config.Name, "status", resp.StatusCode, "body", truncate(body, maxLoggedBody))
+		return nil, fmt.Errorf("backend returned status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// maxLoggedBody 是日志中记录的后端错误响应体的最大字节数
+const maxLoggedBody = 1024
+
+func truncate(b []byte, n int) string {
+	if len(b) <= n {
+		return string(b)
+	}
+	return string(b[:n]) + "…"
+}
+
+// render 将后端响应转换为工具结果：JSON 对象作为 structuredContent，模板渲染结果作为文本内容
+func (t *DeclaredTool) render(body []byte) (*mcp.CallToolResult, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		// 非 JSON 响应按纯文本返回
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: string(body)}},
+		}, nil
+	}
+
+	text := string(body)
+	if t.tmpl != nil {
+		var sb strings.Builder
+		if err := t.tmpl.Execute(&sb, data); err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("failed to render response: %v", err)},
+				},
+				IsError: true,
+			}, nil
+		}
+		text = sb.String()
+	}
+
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}
+	if _, ok := data.(map[string]interface{}); ok {
+		result.StructuredContent = json.RawMessage(body)
+	}
+	return result, nil
+}
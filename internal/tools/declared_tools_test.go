@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp/config"
+	"mcp/internal/reqctx"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func newHTTPTool(t *testing.T, url string, headers map[string]string) *DeclaredTool {
+	tool, err := NewDeclaredTool(context.Background(), config.DeclaredToolConfig{
+		Name:        "weather",
+		InputSchema: `{"type":"object","properties":{"city":{"type":"string"}}}`,
+		Backend: config.BackendBindingConfig{
+			Type:       "http",
+			URL:        url,
+			HTTPMethod: http.MethodPost,
+			Headers:    headers,
+			Timeout:    time.Second,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tool
+}
+
+func resultText(res *mcp.CallToolResult) string {
+	var sb strings.Builder
+	for _, c := range res.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			sb.WriteString(text.Text)
+		}
+	}
+	return sb.String()
+}
+
+func TestDeclaredHTTPToolForwardsCallerIdentity(t *testing.T) {
+	var got http.Header
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer backend.Close()
+	tool := newHTTPTool(t, backend.URL, map[string]string{"X-Request-Id": "from-config"})
+
+	tests := []struct {
+		name string
+		info *reqctx.Info
+		want map[string]string
+	}{
+		{
+			name: "authenticated caller",
+			info: &reqctx.Info{
+				APIKey:      "alicekey",
+				Principal:   &reqctx.Principal{UserID: "alice", Scopes: []string{"diary:read", "memory:read"}},
+				SessionID:   "s1",
+				TraceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			},
+			want: map[string]string{
+				"X-User-Id":        "alice",
+				"X-User-Scopes":    "diary:read memory:read",
+				"X-Api-Key":        "",
+				"X-Mcp-Session-Id": "s1",
+				"X-Request-Id":     "from-config",
+			},
+		},
+		{
+			name: "unauthenticated caller",
+			info: &reqctx.Info{APIKey: "alicekey"},
+			want: map[string]string{"X-Api-Key": "alicekey", "X-User-Id": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tool.Handle(reqctx.With(context.Background(), tt.info), []byte(`{"city":"x"}`))
+			if err != nil || res.IsError {
+				t.Fatalf("Handle: %v %s", err, resultText(res))
+			}
+			for k, v := range tt.want {
+				if got.Get(k) != v {
+					t.Errorf("header %s = %q, want %q", k, got.Get(k), v)
+				}
+			}
+			if tt.info.TraceParent != "" {
+				tp := got.Get("Traceparent")
+				if !strings.HasPrefix(tp, "00-0af7651916cd43dd8448eb211c80319c-") || tp == tt.info.TraceParent {
+					t.Errorf("traceparent = %q, want a child span of the caller's trace", tp)
+				}
+			}
+		})
+	}
+}
+
+func TestDeclaredHTTPToolHidesErrorBody(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "java.sql.SQLException: password=secret", http.StatusInternalServerError)
+	}))
+	defer backend.Close()
+	tool := newHTTPTool(t, backend.URL, nil)
+
+	res, err := tool.Handle(context.Background(), []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	text := resultText(res)
+	if !res.IsError || !strings.Contains(text, "500") || strings.Contains(text, "secret") {
+		t.Fatalf("result = %q (isError %v), want a 500 error without the response body", text, res.IsError)
+	}
+}
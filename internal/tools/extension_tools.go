@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"mcp/internal/grpc"
+	"mcp/internal/reqctx"
 	pb "mcp/proto"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -17,6 +18,19 @@ type SearchDiaryArgs struct {
 	EndTime   string `json:"endTime,omitempty"`
 }
 
+// DiaryItem 是 diarySearch 结构化输出中的单条日记
+type DiaryItem struct {
+	DiaryID string `json:"diaryId"`
+	Date    string `json:"date"`
+	Content string `json:"content"`
+	Emotion string `json:"emotion,omitempty"`
+}
+
+// SearchDiaryOutput 是 diarySearch 的结构化输出 (structuredContent)
+type SearchDiaryOutput struct {
+	Diaries []DiaryItem `json:"diaries"`
+}
+
 // SearchDiaryTool 用于执行后端的 SearchDiary gRPC 方法
 type SearchDiaryTool struct{}
 
@@ -53,7 +67,7 @@ func (t *SearchDiaryTool) GetToolDef() *mcp.Tool {
 
 // Execute 真正执行日记搜索请求
 func (t *SearchDiaryTool) Execute(ctx context.Context, req *mcp.CallToolRequest, args SearchDiaryArgs) (*mcp.CallToolResult, any, error) {
-	apiKey, _ := ctx.Value("apiKey").(string)
+	apiKey := reqctx.BackendAPIKey(ctx)
 
 	grpcReq := &pb.SearchDiaryRequest{
 		ApiKey:    apiKey,
@@ -73,9 +87,16 @@ func (t *SearchDiaryTool) Execute(ctx context.Context, req *mcp.CallToolRequest,
 	}
 
 	var textContent string
+	output := SearchDiaryOutput{Diaries: make([]DiaryItem, 0, len(res.Results))}
 	for i, r := range res.Results {
 		textContent += fmt.Sprintf("Diary %d [%s] (ID: %s, Emotion: %s):\n%s\n\n",
 			i+1, r.Date, r.DiaryId, r.Emotion, r.Content)
+		output.Diaries = append(output.Diaries, DiaryItem{
+			DiaryID: r.DiaryId,
+			Date:    r.Date,
+			Content: r.Content,
+			Emotion: r.Emotion,
+		})
 	}
 
 	if textContent == "" {
@@ -86,6 +107,7 @@ func (t *SearchDiaryTool) Execute(ctx context.Context, req *mcp.CallToolRequest,
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: textContent},
 		},
+		StructuredContent: output,
 	}, nil, nil
 }
 
@@ -95,6 +117,20 @@ type SearchMemoryArgs struct {
 	MaxResults int32  `json:"maxResults,omitempty"`
 }
 
+// MemoryItem 是 memorySearch 结构化输出中的单条记忆
+type MemoryItem struct {
+	Type      string  `json:"type"`
+	Content   string  `json:"content"`
+	SourceID  string  `json:"sourceId"`
+	Score     float64 `json:"score"`
+	CreatedAt string  `json:"createdAt,omitempty"`
+}
+
+// SearchMemoryOutput 是 memorySearch 的结构化输出 (structuredContent)
+type SearchMemoryOutput struct {
+	Memories []MemoryItem `json:"memories"`
+}
+
 // SearchMemoryTool 用于执行后端的 SearchMemory gRPC 方法
 type SearchMemoryTool struct{}
 
@@ -127,7 +163,7 @@ func (t *SearchMemoryTool) GetToolDef() *mcp.Tool {
 
 // Execute 真正执行记忆搜索请求
 func (t *SearchMemoryTool) Execute(ctx context.Context, req *mcp.CallToolRequest, args SearchMemoryArgs) (*mcp.CallToolResult, any, error) {
-	apiKey, _ := ctx.Value("apiKey").(string)
+	apiKey := reqctx.BackendAPIKey(ctx)
 
 	maxResults := args.MaxResults
 	if maxResults <= 0 {
@@ -151,9 +187,17 @@ func (t *SearchMemoryTool) Execute(ctx context.Context, req *mcp.CallToolRequest
 	}
 
 	var textContent string
+	output := SearchMemoryOutput{Memories: make([]MemoryItem, 0, len(res.Results))}
 	for i, r := range res.Results {
 		textContent += fmt.Sprintf("Memory %d [Type: %s] (Score: %.2f, Source: %s):\n%s\n\n",
 			i+1, r.Type, r.Score, r.SourceId, r.Content)
+		output.Memories = append(output.Memories, MemoryItem{
+			Type:      r.Type,
+			Content:   r.Content,
+			SourceID:  r.SourceId,
+			Score:     r.Score,
+			CreatedAt: r.CreatedAt,
+		})
 	}
 
 	if textContent == "" {
@@ -164,5 +208,235 @@ func (t *SearchMemoryTool) Execute(ctx context.Context, req *mcp.CallToolRequest
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: textContent},
 		},
+		StructuredContent: output,
 	}, nil, nil
 }
+
+const (
+	// defaultLifeGraphDepth 未指定遍历深度时的默认值
+	defaultLifeGraphDepth = 1
+	// maxLifeGraphDepth 关系遍历的最大深度，避免一次查询展开整张图谱
+	maxLifeGraphDepth = 3
+)
+
+// SearchLifeGraphArgs 定义了 lifeGraph 工具的入参结构
+type SearchLifeGraphArgs struct {
+	Query         string   `json:"query,omitempty"`
+	EntityID      string   `json:"entityId,omitempty"`
+	EntityTypes   []string `json:"entityTypes,omitempty"`
+	RelationTypes []string `json:"relationTypes,omitempty"`
+	Depth         *int32   `json:"depth,omitempty"`
+	StartTime     string   `json:"startTime,omitempty"`
+	EndTime       string   `json:"endTime,omitempty"`
+	MaxResults    int32    `json:"maxResults,omitempty"`
+}
+
+// GraphEntityItem 是 lifeGraph 结构化输出中的实体
+type GraphEntityItem struct {
+	EntityID    string            `json:"entityId"`
+	Type        string            `json:"type"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Time        string            `json:"time,omitempty"`
+	Location    string            `json:"location,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+}
+
+// GraphRelationItem 是 lifeGraph 结构化输出中的关系
+type GraphRelationItem struct {
+	SourceID    string  `json:"sourceId"`
+	TargetID    string  `json:"targetId"`
+	Type        string  `json:"type"`
+	Description string  `json:"description,omitempty"`
+	StartTime   string  `json:"startTime,omitempty"`
+	EndTime     string  `json:"endTime,omitempty"`
+	Weight      float64 `json:"weight,omitempty"`
+}
+
+// SearchLifeGraphOutput 是 lifeGraph 的结构化输出 (structuredContent)
+type SearchLifeGraphOutput struct {
+	Entities  []GraphEntityItem   `json:"entities"`
+	Relations []GraphRelationItem `json:"relations"`
+}
+
+// SearchLifeGraphTool 用于执行后端的 SearchLifeGraph gRPC 方法
+type SearchLifeGraphTool struct{}
+
+// NewSearchLifeGraphTool 创建一个新的 SearchLifeGraphTool 实例
+func NewSearchLifeGraphTool() *SearchLifeGraphTool {
+	return &SearchLifeGraphTool{}
+}
+
+// GetToolDef 返回该工具在 MCP 中注册的定义
+func (t *SearchLifeGraphTool) GetToolDef() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "lifeGraph",
+		Description: "查询用户的人生图谱（时空关系知识库），获取人物、事件、地点及其之间的关系。可以按名称查找实体，也可以从某个实体出发按关系遍历，并按时间范围过滤。",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "按名称或描述查找实体的关键词，例如人名或事件名",
+				},
+				"entityId": map[string]interface{}{
+					"type":        "string",
+					"description": "从指定实体出发遍历关系（与 query 至少提供一个）",
+				},
+				"entityTypes": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "只返回这些类型的实体，例如 PERSON、EVENT、PLACE",
+				},
+				"relationTypes": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "只沿这些类型的关系遍历",
+				},
+				"depth": map[string]interface{}{
+					"type":        "integer",
+					"minimum":     0,
+					"maximum":     maxLifeGraphDepth,
+					"description": fmt.Sprintf("关系遍历深度，0 表示只查找实体（默认 %d，最大 %d）", defaultLifeGraphDepth, maxLifeGraphDepth),
+				},
+				"startTime": map[string]interface{}{
+					"type":        "string",
+					"description": "开始时间 (格式: yyyy-MM-dd HH:mm:ss)",
+				},
+				"endTime": map[string]interface{}{
+					"type":        "string",
+					"description": "结束时间 (格式: yyyy-MM-dd HH:mm:ss)",
+				},
+				"maxResults": map[string]interface{}{
+					"type":        "integer",
+					"description": "最大返回实体数量（默认 20）",
+				},
+			},
+		},
+	}
+}
+
+// Execute 真正执行人生图谱查询请求
+func (t *SearchLifeGraphTool) Execute(ctx context.Context, req *mcp.CallToolRequest, args SearchLifeGraphArgs) (*mcp.CallToolResult, any, error) {
+	if args.Query == "" && args.EntityID == "" {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: "query 和 entityId 至少需要提供一个"},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	apiKey := reqctx.BackendAPIKey(ctx)
+
+	depth := int32(defaultLifeGraphDepth)
+	if args.Depth != nil {
+		depth = *args.Depth
+	}
+	if depth < 0 {
+		depth = 0
+	}
+	if depth > maxLifeGraphDepth {
+		depth = maxLifeGraphDepth
+	}
+
+	maxResults := args.MaxResults
+	if maxResults <= 0 {
+		maxResults = 20
+	}
+
+	grpcReq := &pb.SearchLifeGraphRequest{
+		ApiKey:        apiKey,
+		Query:         args.Query,
+		EntityId:      args.EntityID,
+		EntityTypes:   args.EntityTypes,
+		RelationTypes: args.RelationTypes,
+		Depth:         depth,
+		StartTime:     args.StartTime,
+		EndTime:       args.EndTime,
+		MaxResults:    maxResults,
+	}
+
+	res, err := grpc.SearchLifeGraph(ctx, grpcReq)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("gRPC error: %v", err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output := SearchLifeGraphOutput{
+		Entities:  make([]GraphEntityItem, 0, len(res.Entities)),
+		Relations: make([]GraphRelationItem, 0, len(res.Relations)),
+	}
+	names := make(map[string]string, len(res.Entities))
+
+	var textContent string
+	for i, e := range res.Entities {
+		names[e.EntityId] = e.Name
+		textContent += fmt.Sprintf("Entity %d [%s] %s (ID: %s)", i+1, e.Type, e.Name, e.EntityId)
+		if e.Time != "" {
+			textContent += fmt.Sprintf(", Time: %s", e.Time)
+		}
+		if e.Location != "" {
+			textContent += fmt.Sprintf(", Location: %s", e.Location)
+		}
+		textContent += "\n"
+		if e.Description != "" {
+			textContent += e.Description + "\n"
+		}
+		output.Entities = append(output.Entities, GraphEntityItem{
+			EntityID:    e.EntityId,
+			Type:        e.Type,
+			Name:        e.Name,
+			Description: e.Description,
+			Time:        e.Time,
+			Location:    e.Location,
+			Properties:  e.Properties,
+		})
+	}
+
+	if len(res.Relations) > 0 {
+		textContent += "\nRelations:\n"
+	}
+	for _, r := range res.Relations {
+		textContent += fmt.Sprintf("- %s -[%s]-> %s", entityLabel(names, r.SourceId), r.Type, entityLabel(names, r.TargetId))
+		if r.StartTime != "" || r.EndTime != "" {
+			textContent += fmt.Sprintf(" (%s ~ %s)", r.StartTime, r.EndTime)
+		}
+		if r.Description != "" {
+			textContent += ": " + r.Description
+		}
+		textContent += "\n"
+		output.Relations = append(output.Relations, GraphRelationItem{
+			SourceID:    r.SourceId,
+			TargetID:    r.TargetId,
+			Type:        r.Type,
+			Description: r.Description,
+			StartTime:   r.StartTime,
+			EndTime:     r.EndTime,
+			Weight:      r.Weight,
+		})
+	}
+
+	if textContent == "" {
+		textContent = "No entities found in the life graph matching the query."
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: textContent},
+		},
+		StructuredContent: output,
+	}, nil, nil
+}
+
+// entityLabel 返回关系端点的可读名称，实体不在结果中时退化为 ID
+func entityLabel(names map[string]string, id string) string {
+	if name, ok := names[id]; ok && name != "" {
+		return fmt.Sprintf("%s(%s)", name, id)
+	}
+	return id
+}
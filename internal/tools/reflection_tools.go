@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"mcp/internal/grpc"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxSchemaDepth 限制嵌套消息展开的深度，防止递归消息无限展开
+const maxSchemaDepth = 5
+
+// ReflectedTool 是通过 gRPC server reflection 自动发现的后端方法所对应的工具
+type ReflectedTool struct {
+	Name   string
+	method protoreflect.MethodDescriptor
+}
+
+// NewReflectedTool 为后端方法创建工具，工具名为 prefix 加上首字母小写的方法名
+func NewReflectedTool(method protoreflect.MethodDescriptor, prefix string) *ReflectedTool {
+	name := []rune(string(method.Name()))
+	name[0] = unicode.ToLower(name[0])
+	return &ReflectedTool{
+		Name:   prefix + string(name),
+		method: method,
+	}
+}
+
+// GetToolDef 返回该工具在 MCP 中注册的定义，入参 Schema 由请求消息的描述符推导
+func (t *ReflectedTool) GetToolDef() *mcp.Tool {
+	desc := comments(t.method)
+	if desc == "" {
+		desc = fmt.Sprintf("调用后端 gRPC 方法 %s/%s", t.method.Parent().FullName(), t.method.Name())
+	}
+	return &mcp.Tool{
+		Name:        t.Name,
+		Description: desc,
+		InputSchema: MessageSchema(t.method.Input()),
+	}
+}
+
+// Handle 执行工具调用，入参按 proto JSON 映射转换为请求消息
+func (t *ReflectedTool) Handle(ctx context.Context, argsJSON json.RawMessage) (*mcp.CallToolResult, error) {
+	body, err := grpc.InvokeJSON(ctx, t.method, argsJSON)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("gRPC error: %v", err)},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(body)}},
+		StructuredContent: json.RawMessage(body),
+	}, nil
+}
+
+// MessageSchema 将 protobuf 消息描述符转换为 JSON Schema
+// api_key 字段总是由服务端按调用方身份覆盖，客户端填写的值会被忽略，因此不暴露给客户端
+func MessageSchema(md protoreflect.MessageDescriptor) map[string]interface{} {
+	schema := messageSchema(md, 0)
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		delete(props, "apiKey")
+	}
+	return schema
+}
+
+func messageSchema(md protoreflect.MessageDescriptor, depth int) map[string]interface{} {
+	if s := wellKnownSchema(md); s != nil {
+		return s
+	}
+
+	schema := map[string]interface{}{"type": "object"}
+	if depth >= maxSchemaDepth {
+		return schema
+	}
+
+	props := make(map[string]interface{}, md.Fields().Len())
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		prop := fieldSchema(fd, depth)
+		if c := comments(fd); c != "" {
+			prop["description"] = c
+		}
+		props[fd.JSONName()] = prop
+	}
+	schema["properties"] = props
+	return schema
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor, depth int) map[string]interface{} {
+	if fd.IsMap() {
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": singularSchema(fd.MapValue(), depth),
+		}
+	}
+	if fd.IsList() {
+		return map[string]interface{}{
+			"type":  "array",
+			"items": singularSchema(fd, depth),
+		}
+	}
+	return singularSchema(fd, depth)
+}
+
+func singularSchema(fd protoreflect.FieldDescriptor, depth int) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageSchema(fd.Message(), depth+1)
+	default:
+		// 各类整数；64 位整数在 proto JSON 中也接受数字形式
+		return map[string]interface{}{"type": "integer"}
+	}
+}
+
+// wellKnownSchema 返回 google.protobuf 常用类型在 proto JSON 映射下的 Schema
+func wellKnownSchema(md protoreflect.MessageDescriptor) map[string]interface{} {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case "google.protobuf.Duration":
+		return map[string]interface{}{"type": "string", "description": "例如 1.5s"}
+	case "google.protobuf.Struct", "google.protobuf.Any":
+		return map[string]interface{}{"type": "object"}
+	case "google.protobuf.Value":
+		return map[string]interface{}{}
+	case "google.protobuf.ListValue":
+		return map[string]interface{}{"type": "array"}
+	case "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return map[string]interface{}{"type": "string"}
+	case "google.protobuf.BoolValue":
+		return map[string]interface{}{"type": "boolean"}
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return map[string]interface{}{"type": "number"}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return map[string]interface{}{"type": "integer"}
+	}
+	return nil
+}
+
+// comments 返回描述符在 proto 源码中的注释
+func comments(d protoreflect.Descriptor) string {
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	return strings.TrimSpace(loc.LeadingComments + loc.TrailingComments)
+}
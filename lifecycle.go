@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// drainPollInterval 排空期间检查进行中请求数的间隔
+const drainPollInterval = 100 * time.Millisecond
+
+// lifecycle 记录服务是否处于排空状态以及进行中的 MCP 请求数
+type lifecycle struct {
+	draining atomic.Bool
+	inflight atomic.Int64
+}
+
+// Draining 返回服务是否正在排空，排空期间不再接受新的会话
+func (s *MCPServer) Draining() bool {
+	return s.lifecycle.draining.Load()
+}
+
+// Drain 进入排空状态并等待进行中的请求完成，ctx 到期时返回其错误
+// 已建立的会话在排空期间仍可发起请求
+func (s *MCPServer) Drain(ctx context.Context) error {
+	s.lifecycle.draining.Store(true)
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for s.lifecycle.inflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// CloseSessions 关闭全部会话，SSE 与 Streamable HTTP 的推送流随之结束
+func (s *MCPServer) CloseSessions() int {
+	n := 0
+	for ss := range s.Server.Sessions() {
+		ss.Close()
+		n++
+	}
+	return n
+}
+
+// inflightMiddleware 统计进行中的 MCP 请求，供 Drain 等待
+func inflightMiddleware(l *lifecycle) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			l.inflight.Add(1)
+			defer l.inflight.Add(-1)
+			return next(ctx, method, req)
+		}
+	}
+}
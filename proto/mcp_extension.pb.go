@@ -399,6 +399,1187 @@ func (x *MemoryResult) GetCreatedAt() string {
 	return ""
 }
 
+type SearchLifeGraphRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Free-text entity lookup, e.g. a person's name or an event title. Can be empty when entity_id is set.
+	Query string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	// Start the traversal from this entity. Can be empty.
+	EntityId string `protobuf:"bytes,3,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	// Restrict results to these entity types (PERSON, EVENT, PLACE, ...). Empty means all.
+	EntityTypes []string `protobuf:"bytes,4,rep,name=entity_types,json=entityTypes,proto3" json:"entity_types,omitempty"`
+	// Restrict the traversal to these relation types. Empty means all.
+	RelationTypes []string `protobuf:"bytes,5,rep,name=relation_types,json=relationTypes,proto3" json:"relation_types,omitempty"`
+	// Maximum number of hops from the matched entities. 0 means entity lookup only.
+	Depth int32 `protobuf:"varint,6,opt,name=depth,proto3" json:"depth,omitempty"`
+	// Format: yyyy-MM-dd HH:mm:ss. Can be empty.
+	StartTime string `protobuf:"bytes,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Format: yyyy-MM-dd HH:mm:ss. Can be empty.
+	EndTime       string `protobuf:"bytes,8,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	MaxResults    int32  `protobuf:"varint,9,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchLifeGraphRequest) Reset() {
+	*x = SearchLifeGraphRequest{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchLifeGraphRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchLifeGraphRequest) ProtoMessage() {}
+
+func (x *SearchLifeGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchLifeGraphRequest.ProtoReflect.Descriptor instead.
+func (*SearchLifeGraphRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchLifeGraphRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SearchLifeGraphRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchLifeGraphRequest) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *SearchLifeGraphRequest) GetEntityTypes() []string {
+	if x != nil {
+		return x.EntityTypes
+	}
+	return nil
+}
+
+func (x *SearchLifeGraphRequest) GetRelationTypes() []string {
+	if x != nil {
+		return x.RelationTypes
+	}
+	return nil
+}
+
+func (x *SearchLifeGraphRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *SearchLifeGraphRequest) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *SearchLifeGraphRequest) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *SearchLifeGraphRequest) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+type SearchLifeGraphResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entities      []*GraphEntity         `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	Relations     []*GraphRelation       `protobuf:"bytes,2,rep,name=relations,proto3" json:"relations,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchLifeGraphResponse) Reset() {
+	*x = SearchLifeGraphResponse{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchLifeGraphResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchLifeGraphResponse) ProtoMessage() {}
+
+func (x *SearchLifeGraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchLifeGraphResponse.ProtoReflect.Descriptor instead.
+func (*SearchLifeGraphResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchLifeGraphResponse) GetEntities() []*GraphEntity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *SearchLifeGraphResponse) GetRelations() []*GraphRelation {
+	if x != nil {
+		return x.Relations
+	}
+	return nil
+}
+
+func (x *SearchLifeGraphResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type GraphEntity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityId      string                 `protobuf:"bytes,1,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // PERSON, EVENT, PLACE, ...
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Time          string                 `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`         // when the event happened, if any
+	Location      string                 `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"` // where the event happened, if any
+	Properties    map[string]string      `protobuf:"bytes,7,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GraphEntity) Reset() {
+	*x = GraphEntity{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GraphEntity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphEntity) ProtoMessage() {}
+
+func (x *GraphEntity) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphEntity.ProtoReflect.Descriptor instead.
+func (*GraphEntity) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GraphEntity) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *GraphEntity) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GraphEntity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GraphEntity) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *GraphEntity) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *GraphEntity) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *GraphEntity) GetProperties() map[string]string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type GraphRelation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SourceId      string                 `protobuf:"bytes,1,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	TargetId      string                 `protobuf:"bytes,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"` // e.g. FRIEND_OF, PARTICIPATED_IN, HAPPENED_AT
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	StartTime     string                 `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       string                 `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Weight        float64                `protobuf:"fixed64,7,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GraphRelation) Reset() {
+	*x = GraphRelation{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GraphRelation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphRelation) ProtoMessage() {}
+
+func (x *GraphRelation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphRelation.ProtoReflect.Descriptor instead.
+func (*GraphRelation) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GraphRelation) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+func (x *GraphRelation) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *GraphRelation) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GraphRelation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *GraphRelation) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *GraphRelation) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *GraphRelation) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type GetDiaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	DiaryId       string                 `protobuf:"bytes,2,opt,name=diary_id,json=diaryId,proto3" json:"diary_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDiaryRequest) Reset() {
+	*x = GetDiaryRequest{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDiaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDiaryRequest) ProtoMessage() {}
+
+func (x *GetDiaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDiaryRequest.ProtoReflect.Descriptor instead.
+func (*GetDiaryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetDiaryRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *GetDiaryRequest) GetDiaryId() string {
+	if x != nil {
+		return x.DiaryId
+	}
+	return ""
+}
+
+type GetDiaryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unset if the diary does not exist or does not belong to the user.
+	Diary         *DiaryResult `protobuf:"bytes,1,opt,name=diary,proto3" json:"diary,omitempty"`
+	ErrorMessage  string       `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDiaryResponse) Reset() {
+	*x = GetDiaryResponse{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDiaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDiaryResponse) ProtoMessage() {}
+
+func (x *GetDiaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDiaryResponse.ProtoReflect.Descriptor instead.
+func (*GetDiaryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetDiaryResponse) GetDiary() *DiaryResult {
+	if x != nil {
+		return x.Diary
+	}
+	return nil
+}
+
+func (x *GetDiaryResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type ListDiariesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	MaxResults    int32                  `protobuf:"varint,2,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDiariesRequest) Reset() {
+	*x = ListDiariesRequest{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDiariesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDiariesRequest) ProtoMessage() {}
+
+func (x *ListDiariesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDiariesRequest.ProtoReflect.Descriptor instead.
+func (*ListDiariesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListDiariesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ListDiariesRequest) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+type ListDiariesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Diaries       []*DiaryResult         `protobuf:"bytes,1,rep,name=diaries,proto3" json:"diaries,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDiariesResponse) Reset() {
+	*x = ListDiariesResponse{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDiariesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDiariesResponse) ProtoMessage() {}
+
+func (x *ListDiariesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDiariesResponse.ProtoReflect.Descriptor instead.
+func (*ListDiariesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListDiariesResponse) GetDiaries() []*DiaryResult {
+	if x != nil {
+		return x.Diaries
+	}
+	return nil
+}
+
+func (x *ListDiariesResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type GetMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	SourceId      string                 `protobuf:"bytes,2,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMemoryRequest) Reset() {
+	*x = GetMemoryRequest{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMemoryRequest) ProtoMessage() {}
+
+func (x *GetMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMemoryRequest.ProtoReflect.Descriptor instead.
+func (*GetMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetMemoryRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *GetMemoryRequest) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+type GetMemoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unset if the memory does not exist or does not belong to the user.
+	Memory        *MemoryResult `protobuf:"bytes,1,opt,name=memory,proto3" json:"memory,omitempty"`
+	ErrorMessage  string        `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMemoryResponse) Reset() {
+	*x = GetMemoryResponse{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMemoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMemoryResponse) ProtoMessage() {}
+
+func (x *GetMemoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMemoryResponse.ProtoReflect.Descriptor instead.
+func (*GetMemoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetMemoryResponse) GetMemory() *MemoryResult {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *GetMemoryResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type ListMemoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	MaxResults    int32                  `protobuf:"varint,2,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMemoriesRequest) Reset() {
+	*x = ListMemoriesRequest{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMemoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMemoriesRequest) ProtoMessage() {}
+
+func (x *ListMemoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMemoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListMemoriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListMemoriesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ListMemoriesRequest) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+type ListMemoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Memories      []*MemoryResult        `protobuf:"bytes,1,rep,name=memories,proto3" json:"memories,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMemoriesResponse) Reset() {
+	*x = ListMemoriesResponse{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMemoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMemoriesResponse) ProtoMessage() {}
+
+func (x *ListMemoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMemoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListMemoriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListMemoriesResponse) GetMemories() []*MemoryResult {
+	if x != nil {
+		return x.Memories
+	}
+	return nil
+}
+
+func (x *ListMemoriesResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type ValidateApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateApiKeyRequest) Reset() {
+	*x = ValidateApiKeyRequest{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateApiKeyRequest) ProtoMessage() {}
+
+func (x *ValidateApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*ValidateApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ValidateApiKeyRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type ValidateApiKeyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// False if the key is unknown, revoked or expired.
+	Valid  bool   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Scopes granted to the key, e.g. diary:read, memory:read.
+	Scopes        []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ErrorMessage  string   `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateApiKeyResponse) Reset() {
+	*x = ValidateApiKeyResponse{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateApiKeyResponse) ProtoMessage() {}
+
+func (x *ValidateApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*ValidateApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ValidateApiKeyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateApiKeyResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ValidateApiKeyResponse) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ValidateApiKeyResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type ListToolsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListToolsRequest) Reset() {
+	*x = ListToolsRequest{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListToolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListToolsRequest) ProtoMessage() {}
+
+func (x *ListToolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListToolsRequest.ProtoReflect.Descriptor instead.
+func (*ListToolsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{20}
+}
+
+type ListToolsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tools         []*ToolDefinition      `protobuf:"bytes,1,rep,name=tools,proto3" json:"tools,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListToolsResponse) Reset() {
+	*x = ListToolsResponse{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListToolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListToolsResponse) ProtoMessage() {}
+
+func (x *ListToolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListToolsResponse.ProtoReflect.Descriptor instead.
+func (*ListToolsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListToolsResponse) GetTools() []*ToolDefinition {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+func (x *ListToolsResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type ToolDefinition struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// JSON Schema of the arguments, encoded as a JSON object. Must have "type": "object".
+	InputSchemaJson string `protobuf:"bytes,3,opt,name=input_schema_json,json=inputSchemaJson,proto3" json:"input_schema_json,omitempty"`
+	// Optional JSON Schema of the structured result, encoded as a JSON object.
+	OutputSchemaJson string `protobuf:"bytes,4,opt,name=output_schema_json,json=outputSchemaJson,proto3" json:"output_schema_json,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ToolDefinition) Reset() {
+	*x = ToolDefinition{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolDefinition) ProtoMessage() {}
+
+func (x *ToolDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolDefinition.ProtoReflect.Descriptor instead.
+func (*ToolDefinition) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ToolDefinition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ToolDefinition) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ToolDefinition) GetInputSchemaJson() string {
+	if x != nil {
+		return x.InputSchemaJson
+	}
+	return ""
+}
+
+func (x *ToolDefinition) GetOutputSchemaJson() string {
+	if x != nil {
+		return x.OutputSchemaJson
+	}
+	return ""
+}
+
+type CallToolRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Name   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Arguments encoded as a JSON object.
+	ArgumentsJson string `protobuf:"bytes,3,opt,name=arguments_json,json=argumentsJson,proto3" json:"arguments_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallToolRequest) Reset() {
+	*x = CallToolRequest{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallToolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallToolRequest) ProtoMessage() {}
+
+func (x *CallToolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallToolRequest.ProtoReflect.Descriptor instead.
+func (*CallToolRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CallToolRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *CallToolRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CallToolRequest) GetArgumentsJson() string {
+	if x != nil {
+		return x.ArgumentsJson
+	}
+	return ""
+}
+
+type CallToolResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Human-readable result returned to the MCP client as text content.
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Optional structured result encoded as a JSON object.
+	StructuredContentJson string `protobuf:"bytes,2,opt,name=structured_content_json,json=structuredContentJson,proto3" json:"structured_content_json,omitempty"`
+	// True if the tool ran but failed; the text describes the failure.
+	IsError       bool   `protobuf:"varint,3,opt,name=is_error,json=isError,proto3" json:"is_error,omitempty"`
+	ErrorMessage  string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallToolResponse) Reset() {
+	*x = CallToolResponse{}
+	mi := &file_proto_mcp_extension_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallToolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallToolResponse) ProtoMessage() {}
+
+func (x *CallToolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mcp_extension_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallToolResponse.ProtoReflect.Descriptor instead.
+func (*CallToolResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mcp_extension_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CallToolResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CallToolResponse) GetStructuredContentJson() string {
+	if x != nil {
+		return x.StructuredContentJson
+	}
+	return ""
+}
+
+func (x *CallToolResponse) GetIsError() bool {
+	if x != nil {
+		return x.IsError
+	}
+	return false
+}
+
+func (x *CallToolResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
 var File_proto_mcp_extension_proto protoreflect.FileDescriptor
 
 const file_proto_mcp_extension_proto_rawDesc = "" +
@@ -432,10 +1613,107 @@ const file_proto_mcp_extension_proto_rawDesc = "" +
 	"\tsource_id\x18\x03 \x01(\tR\bsourceId\x12\x14\n" +
 	"\x05score\x18\x04 \x01(\x01R\x05score\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\tR\tcreatedAt2\xc8\x01\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\"\x9f\x02\n" +
+	"\x16SearchLifeGraphRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x1b\n" +
+	"\tentity_id\x18\x03 \x01(\tR\bentityId\x12!\n" +
+	"\fentity_types\x18\x04 \x03(\tR\ventityTypes\x12%\n" +
+	"\x0erelation_types\x18\x05 \x03(\tR\rrelationTypes\x12\x14\n" +
+	"\x05depth\x18\x06 \x01(\x05R\x05depth\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\a \x01(\tR\tstartTime\x12\x19\n" +
+	"\bend_time\x18\b \x01(\tR\aendTime\x12\x1f\n" +
+	"\vmax_results\x18\t \x01(\x05R\n" +
+	"maxResults\"\xb2\x01\n" +
+	"\x17SearchLifeGraphResponse\x126\n" +
+	"\bentities\x18\x01 \x03(\v2\x1a.mcp.extension.GraphEntityR\bentities\x12:\n" +
+	"\trelations\x18\x02 \x03(\v2\x1c.mcp.extension.GraphRelationR\trelations\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\xaf\x02\n" +
+	"\vGraphEntity\x12\x1b\n" +
+	"\tentity_id\x18\x01 \x01(\tR\bentityId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04time\x18\x05 \x01(\tR\x04time\x12\x1a\n" +
+	"\blocation\x18\x06 \x01(\tR\blocation\x12J\n" +
+	"\n" +
+	"properties\x18\a \x03(\v2*.mcp.extension.GraphEntity.PropertiesEntryR\n" +
+	"properties\x1a=\n" +
+	"\x0fPropertiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd1\x01\n" +
+	"\rGraphRelation\x12\x1b\n" +
+	"\tsource_id\x18\x01 \x01(\tR\bsourceId\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\tR\btargetId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\tR\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x06 \x01(\tR\aendTime\x12\x16\n" +
+	"\x06weight\x18\a \x01(\x01R\x06weight\"E\n" +
+	"\x0fGetDiaryRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x19\n" +
+	"\bdiary_id\x18\x02 \x01(\tR\adiaryId\"i\n" +
+	"\x10GetDiaryResponse\x120\n" +
+	"\x05diary\x18\x01 \x01(\v2\x1a.mcp.extension.DiaryResultR\x05diary\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"N\n" +
+	"\x12ListDiariesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1f\n" +
+	"\vmax_results\x18\x02 \x01(\x05R\n" +
+	"maxResults\"p\n" +
+	"\x13ListDiariesResponse\x124\n" +
+	"\adiaries\x18\x01 \x03(\v2\x1a.mcp.extension.DiaryResultR\adiaries\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"H\n" +
+	"\x10GetMemoryRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tsource_id\x18\x02 \x01(\tR\bsourceId\"m\n" +
+	"\x11GetMemoryResponse\x123\n" +
+	"\x06memory\x18\x01 \x01(\v2\x1b.mcp.extension.MemoryResultR\x06memory\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"O\n" +
+	"\x13ListMemoriesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1f\n" +
+	"\vmax_results\x18\x02 \x01(\x05R\n" +
+	"maxResults\"t\n" +
+	"\x14ListMemoriesResponse\x127\n" +
+	"\bmemories\x18\x01 \x03(\v2\x1b.mcp.extension.MemoryResultR\bmemories\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"0\n" +
+	"\x15ValidateApiKeyRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"\x84\x01\n" +
+	"\x16ValidateApiKeyResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"\x12\n" +
+	"\x10ListToolsRequest\"m\n" +
+	"\x11ListToolsResponse\x123\n" +
+	"\x05tools\x18\x01 \x03(\v2\x1d.mcp.extension.ToolDefinitionR\x05tools\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"\xa0\x01\n" +
+	"\x0eToolDefinition\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12*\n" +
+	"\x11input_schema_json\x18\x03 \x01(\tR\x0finputSchemaJson\x12,\n" +
+	"\x12output_schema_json\x18\x04 \x01(\tR\x10outputSchemaJson\"e\n" +
+	"\x0fCallToolRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12%\n" +
+	"\x0earguments_json\x18\x03 \x01(\tR\rargumentsJson\"\x9e\x01\n" +
+	"\x10CallToolResponse\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x126\n" +
+	"\x17structured_content_json\x18\x02 \x01(\tR\x15structuredContentJson\x12\x19\n" +
+	"\bis_error\x18\x03 \x01(\bR\aisError\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage2\x82\a\n" +
 	"\x13McpExtensionService\x12V\n" +
 	"\vSearchDiary\x12!.mcp.extension.SearchDiaryRequest\x1a\".mcp.extension.SearchDiaryResponse\"\x00\x12Y\n" +
-	"\fSearchMemory\x12\".mcp.extension.SearchMemoryRequest\x1a#.mcp.extension.SearchMemoryResponse\"\x00B;\n" +
+	"\fSearchMemory\x12\".mcp.extension.SearchMemoryRequest\x1a#.mcp.extension.SearchMemoryResponse\"\x00\x12b\n" +
+	"\x0fSearchLifeGraph\x12%.mcp.extension.SearchLifeGraphRequest\x1a&.mcp.extension.SearchLifeGraphResponse\"\x00\x12M\n" +
+	"\bGetDiary\x12\x1e.mcp.extension.GetDiaryRequest\x1a\x1f.mcp.extension.GetDiaryResponse\"\x00\x12V\n" +
+	"\vListDiaries\x12!.mcp.extension.ListDiariesRequest\x1a\".mcp.extension.ListDiariesResponse\"\x00\x12P\n" +
+	"\tGetMemory\x12\x1f.mcp.extension.GetMemoryRequest\x1a .mcp.extension.GetMemoryResponse\"\x00\x12Y\n" +
+	"\fListMemories\x12\".mcp.extension.ListMemoriesRequest\x1a#.mcp.extension.ListMemoriesResponse\"\x00\x12_\n" +
+	"\x0eValidateApiKey\x12$.mcp.extension.ValidateApiKeyRequest\x1a%.mcp.extension.ValidateApiKeyResponse\"\x00\x12P\n" +
+	"\tListTools\x12\x1f.mcp.extension.ListToolsRequest\x1a .mcp.extension.ListToolsResponse\"\x00\x12M\n" +
+	"\bCallTool\x12\x1e.mcp.extension.CallToolRequest\x1a\x1f.mcp.extension.CallToolResponse\"\x00B;\n" +
 	"\x19com.aseubel.yusi.grpc.mcpB\x11McpExtensionProtoP\x01Z\tmcp/protob\x06proto3"
 
 var (
@@ -450,27 +1728,71 @@ func file_proto_mcp_extension_proto_rawDescGZIP() []byte {
 	return file_proto_mcp_extension_proto_rawDescData
 }
 
-var file_proto_mcp_extension_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_mcp_extension_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_proto_mcp_extension_proto_goTypes = []any{
-	(*SearchDiaryRequest)(nil),   // 0: mcp.extension.SearchDiaryRequest
-	(*SearchDiaryResponse)(nil),  // 1: mcp.extension.SearchDiaryResponse
-	(*DiaryResult)(nil),          // 2: mcp.extension.DiaryResult
-	(*SearchMemoryRequest)(nil),  // 3: mcp.extension.SearchMemoryRequest
-	(*SearchMemoryResponse)(nil), // 4: mcp.extension.SearchMemoryResponse
-	(*MemoryResult)(nil),         // 5: mcp.extension.MemoryResult
+	(*SearchDiaryRequest)(nil),      // 0: mcp.extension.SearchDiaryRequest
+	(*SearchDiaryResponse)(nil),     // 1: mcp.extension.SearchDiaryResponse
+	(*DiaryResult)(nil),             // 2: mcp.extension.DiaryResult
+	(*SearchMemoryRequest)(nil),     // 3: mcp.extension.SearchMemoryRequest
+	(*SearchMemoryResponse)(nil),    // 4: mcp.extension.SearchMemoryResponse
+	(*MemoryResult)(nil),            // 5: mcp.extension.MemoryResult
+	(*SearchLifeGraphRequest)(nil),  // 6: mcp.extension.SearchLifeGraphRequest
+	(*SearchLifeGraphResponse)(nil), // 7: mcp.extension.SearchLifeGraphResponse
+	(*GraphEntity)(nil),             // 8: mcp.extension.GraphEntity
+	(*GraphRelation)(nil),           // 9: mcp.extension.GraphRelation
+	(*GetDiaryRequest)(nil),         // 10: mcp.extension.GetDiaryRequest
+	(*GetDiaryResponse)(nil),        // 11: mcp.extension.GetDiaryResponse
+	(*ListDiariesRequest)(nil),      // 12: mcp.extension.ListDiariesRequest
+	(*ListDiariesResponse)(nil),     // 13: mcp.extension.ListDiariesResponse
+	(*GetMemoryRequest)(nil),        // 14: mcp.extension.GetMemoryRequest
+	(*GetMemoryResponse)(nil),       // 15: mcp.extension.GetMemoryResponse
+	(*ListMemoriesRequest)(nil),     // 16: mcp.extension.ListMemoriesRequest
+	(*ListMemoriesResponse)(nil),    // 17: mcp.extension.ListMemoriesResponse
+	(*ValidateApiKeyRequest)(nil),   // 18: mcp.extension.ValidateApiKeyRequest
+	(*ValidateApiKeyResponse)(nil),  // 19: mcp.extension.ValidateApiKeyResponse
+	(*ListToolsRequest)(nil),        // 20: mcp.extension.ListToolsRequest
+	(*ListToolsResponse)(nil),       // 21: mcp.extension.ListToolsResponse
+	(*ToolDefinition)(nil),          // 22: mcp.extension.ToolDefinition
+	(*CallToolRequest)(nil),         // 23: mcp.extension.CallToolRequest
+	(*CallToolResponse)(nil),        // 24: mcp.extension.CallToolResponse
+	nil,                             // 25: mcp.extension.GraphEntity.PropertiesEntry
 }
 var file_proto_mcp_extension_proto_depIdxs = []int32{
-	2, // 0: mcp.extension.SearchDiaryResponse.results:type_name -> mcp.extension.DiaryResult
-	5, // 1: mcp.extension.SearchMemoryResponse.results:type_name -> mcp.extension.MemoryResult
-	0, // 2: mcp.extension.McpExtensionService.SearchDiary:input_type -> mcp.extension.SearchDiaryRequest
-	3, // 3: mcp.extension.McpExtensionService.SearchMemory:input_type -> mcp.extension.SearchMemoryRequest
-	1, // 4: mcp.extension.McpExtensionService.SearchDiary:output_type -> mcp.extension.SearchDiaryResponse
-	4, // 5: mcp.extension.McpExtensionService.SearchMemory:output_type -> mcp.extension.SearchMemoryResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2,  // 0: mcp.extension.SearchDiaryResponse.results:type_name -> mcp.extension.DiaryResult
+	5,  // 1: mcp.extension.SearchMemoryResponse.results:type_name -> mcp.extension.MemoryResult
+	8,  // 2: mcp.extension.SearchLifeGraphResponse.entities:type_name -> mcp.extension.GraphEntity
+	9,  // 3: mcp.extension.SearchLifeGraphResponse.relations:type_name -> mcp.extension.GraphRelation
+	25, // 4: mcp.extension.GraphEntity.properties:type_name -> mcp.extension.GraphEntity.PropertiesEntry
+	2,  // 5: mcp.extension.GetDiaryResponse.diary:type_name -> mcp.extension.DiaryResult
+	2,  // 6: mcp.extension.ListDiariesResponse.diaries:type_name -> mcp.extension.DiaryResult
+	5,  // 7: mcp.extension.GetMemoryResponse.memory:type_name -> mcp.extension.MemoryResult
+	5,  // 8: mcp.extension.ListMemoriesResponse.memories:type_name -> mcp.extension.MemoryResult
+	22, // 9: mcp.extension.ListToolsResponse.tools:type_name -> mcp.extension.ToolDefinition
+	0,  // 10: mcp.extension.McpExtensionService.SearchDiary:input_type -> mcp.extension.SearchDiaryRequest
+	3,  // 11: mcp.extension.McpExtensionService.SearchMemory:input_type -> mcp.extension.SearchMemoryRequest
+	6,  // 12: mcp.extension.McpExtensionService.SearchLifeGraph:input_type -> mcp.extension.SearchLifeGraphRequest
+	10, // 13: mcp.extension.McpExtensionService.GetDiary:input_type -> mcp.extension.GetDiaryRequest
+	12, // 14: mcp.extension.McpExtensionService.ListDiaries:input_type -> mcp.extension.ListDiariesRequest
+	14, // 15: mcp.extension.McpExtensionService.GetMemory:input_type -> mcp.extension.GetMemoryRequest
+	16, // 16: mcp.extension.McpExtensionService.ListMemories:input_type -> mcp.extension.ListMemoriesRequest
+	18, // 17: mcp.extension.McpExtensionService.ValidateApiKey:input_type -> mcp.extension.ValidateApiKeyRequest
+	20, // 18: mcp.extension.McpExtensionService.ListTools:input_type -> mcp.extension.ListToolsRequest
+	23, // 19: mcp.extension.McpExtensionService.CallTool:input_type -> mcp.extension.CallToolRequest
+	1,  // 20: mcp.extension.McpExtensionService.SearchDiary:output_type -> mcp.extension.SearchDiaryResponse
+	4,  // 21: mcp.extension.McpExtensionService.SearchMemory:output_type -> mcp.extension.SearchMemoryResponse
+	7,  // 22: mcp.extension.McpExtensionService.SearchLifeGraph:output_type -> mcp.extension.SearchLifeGraphResponse
+	11, // 23: mcp.extension.McpExtensionService.GetDiary:output_type -> mcp.extension.GetDiaryResponse
+	13, // 24: mcp.extension.McpExtensionService.ListDiaries:output_type -> mcp.extension.ListDiariesResponse
+	15, // 25: mcp.extension.McpExtensionService.GetMemory:output_type -> mcp.extension.GetMemoryResponse
+	17, // 26: mcp.extension.McpExtensionService.ListMemories:output_type -> mcp.extension.ListMemoriesResponse
+	19, // 27: mcp.extension.McpExtensionService.ValidateApiKey:output_type -> mcp.extension.ValidateApiKeyResponse
+	21, // 28: mcp.extension.McpExtensionService.ListTools:output_type -> mcp.extension.ListToolsResponse
+	24, // 29: mcp.extension.McpExtensionService.CallTool:output_type -> mcp.extension.CallToolResponse
+	20, // [20:30] is the sub-list for method output_type
+	10, // [10:20] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_proto_mcp_extension_proto_init() }
@@ -484,7 +1806,7 @@ func file_proto_mcp_extension_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_mcp_extension_proto_rawDesc), len(file_proto_mcp_extension_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
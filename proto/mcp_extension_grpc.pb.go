@@ -19,18 +19,49 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	McpExtensionService_SearchDiary_FullMethodName  = "/mcp.extension.McpExtensionService/SearchDiary"
-	McpExtensionService_SearchMemory_FullMethodName = "/mcp.extension.McpExtensionService/SearchMemory"
+	McpExtensionService_SearchDiary_FullMethodName     = "/mcp.extension.McpExtensionService/SearchDiary"
+	McpExtensionService_SearchMemory_FullMethodName    = "/mcp.extension.McpExtensionService/SearchMemory"
+	McpExtensionService_SearchLifeGraph_FullMethodName = "/mcp.extension.McpExtensionService/SearchLifeGraph"
+	McpExtensionService_GetDiary_FullMethodName        = "/mcp.extension.McpExtensionService/GetDiary"
+	McpExtensionService_ListDiaries_FullMethodName     = "/mcp.extension.McpExtensionService/ListDiaries"
+	McpExtensionService_GetMemory_FullMethodName       = "/mcp.extension.McpExtensionService/GetMemory"
+	McpExtensionService_ListMemories_FullMethodName    = "/mcp.extension.McpExtensionService/ListMemories"
+	McpExtensionService_ValidateApiKey_FullMethodName  = "/mcp.extension.McpExtensionService/ValidateApiKey"
+	McpExtensionService_ListTools_FullMethodName       = "/mcp.extension.McpExtensionService/ListTools"
+	McpExtensionService_CallTool_FullMethodName        = "/mcp.extension.McpExtensionService/CallTool"
 )
 
 // McpExtensionServiceClient is the client API for McpExtensionService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Every call carries the caller's identity as gRPC metadata (see internal/grpc/metadata.go):
+//
+//	x-api-key, x-user-id, x-user-scopes, x-request-id, x-mcp-session-id, traceparent, tracestate
+//
+// The api_key request fields are kept for backward compatibility; new RPCs should
+// authenticate via metadata and need no auth fields.
 type McpExtensionServiceClient interface {
 	// Searches diaries by keyword and time range
 	SearchDiary(ctx context.Context, in *SearchDiaryRequest, opts ...grpc.CallOption) (*SearchDiaryResponse, error)
 	// Searches memories including mid-term memories and short-term conversation context
 	SearchMemory(ctx context.Context, in *SearchMemoryRequest, opts ...grpc.CallOption) (*SearchMemoryResponse, error)
+	// Queries the life graph: people, events, places and their spatio-temporal relations
+	SearchLifeGraph(ctx context.Context, in *SearchLifeGraphRequest, opts ...grpc.CallOption) (*SearchLifeGraphResponse, error)
+	// Fetches a single diary entry by ID
+	GetDiary(ctx context.Context, in *GetDiaryRequest, opts ...grpc.CallOption) (*GetDiaryResponse, error)
+	// Lists the user's diaries, most recent first
+	ListDiaries(ctx context.Context, in *ListDiariesRequest, opts ...grpc.CallOption) (*ListDiariesResponse, error)
+	// Fetches a single memory by its source ID
+	GetMemory(ctx context.Context, in *GetMemoryRequest, opts ...grpc.CallOption) (*GetMemoryResponse, error)
+	// Lists the user's memories, most recent first
+	ListMemories(ctx context.Context, in *ListMemoriesRequest, opts ...grpc.CallOption) (*ListMemoriesResponse, error)
+	// Validates an API key and resolves the user it belongs to
+	ValidateApiKey(ctx context.Context, in *ValidateApiKeyRequest, opts ...grpc.CallOption) (*ValidateApiKeyResponse, error)
+	// Lists the tools the backend advertises to MCP clients
+	ListTools(ctx context.Context, in *ListToolsRequest, opts ...grpc.CallOption) (*ListToolsResponse, error)
+	// Executes a backend-advertised tool by name
+	CallTool(ctx context.Context, in *CallToolRequest, opts ...grpc.CallOption) (*CallToolResponse, error)
 }
 
 type mcpExtensionServiceClient struct {
@@ -61,14 +92,117 @@ func (c *mcpExtensionServiceClient) SearchMemory(ctx context.Context, in *Search
 	return out, nil
 }
 
+func (c *mcpExtensionServiceClient) SearchLifeGraph(ctx context.Context, in *SearchLifeGraphRequest, opts ...grpc.CallOption) (*SearchLifeGraphResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchLifeGraphResponse)
+	err := c.cc.Invoke(ctx, McpExtensionService_SearchLifeGraph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcpExtensionServiceClient) GetDiary(ctx context.Context, in *GetDiaryRequest, opts ...grpc.CallOption) (*GetDiaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDiaryResponse)
+	err := c.cc.Invoke(ctx, McpExtensionService_GetDiary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcpExtensionServiceClient) ListDiaries(ctx context.Context, in *ListDiariesRequest, opts ...grpc.CallOption) (*ListDiariesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDiariesResponse)
+	err := c.cc.Invoke(ctx, McpExtensionService_ListDiaries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcpExtensionServiceClient) GetMemory(ctx context.Context, in *GetMemoryRequest, opts ...grpc.CallOption) (*GetMemoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMemoryResponse)
+	err := c.cc.Invoke(ctx, McpExtensionService_GetMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcpExtensionServiceClient) ListMemories(ctx context.Context, in *ListMemoriesRequest, opts ...grpc.CallOption) (*ListMemoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMemoriesResponse)
+	err := c.cc.Invoke(ctx, McpExtensionService_ListMemories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcpExtensionServiceClient) ValidateApiKey(ctx context.Context, in *ValidateApiKeyRequest, opts ...grpc.CallOption) (*ValidateApiKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateApiKeyResponse)
+	err := c.cc.Invoke(ctx, McpExtensionService_ValidateApiKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcpExtensionServiceClient) ListTools(ctx context.Context, in *ListToolsRequest, opts ...grpc.CallOption) (*ListToolsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListToolsResponse)
+	err := c.cc.Invoke(ctx, McpExtensionService_ListTools_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mcpExtensionServiceClient) CallTool(ctx context.Context, in *CallToolRequest, opts ...grpc.CallOption) (*CallToolResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CallToolResponse)
+	err := c.cc.Invoke(ctx, McpExtensionService_CallTool_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // McpExtensionServiceServer is the server API for McpExtensionService service.
 // All implementations must embed UnimplementedMcpExtensionServiceServer
 // for forward compatibility.
+//
+// Every call carries the caller's identity as gRPC metadata (see internal/grpc/metadata.go):
+//
+//	x-api-key, x-user-id, x-user-scopes, x-request-id, x-mcp-session-id, traceparent, tracestate
+//
+// The api_key request fields are kept for backward compatibility; new RPCs should
+// authenticate via metadata and need no auth fields.
 type McpExtensionServiceServer interface {
 	// Searches diaries by keyword and time range
 	SearchDiary(context.Context, *SearchDiaryRequest) (*SearchDiaryResponse, error)
 	// Searches memories including mid-term memories and short-term conversation context
 	SearchMemory(context.Context, *SearchMemoryRequest) (*SearchMemoryResponse, error)
+	// Queries the life graph: people, events, places and their spatio-temporal relations
+	SearchLifeGraph(context.Context, *SearchLifeGraphRequest) (*SearchLifeGraphResponse, error)
+	// Fetches a single diary entry by ID
+	GetDiary(context.Context, *GetDiaryRequest) (*GetDiaryResponse, error)
+	// Lists the user's diaries, most recent first
+	ListDiaries(context.Context, *ListDiariesRequest) (*ListDiariesResponse, error)
+	// Fetches a single memory by its source ID
+	GetMemory(context.Context, *GetMemoryRequest) (*GetMemoryResponse, error)
+	// Lists the user's memories, most recent first
+	ListMemories(context.Context, *ListMemoriesRequest) (*ListMemoriesResponse, error)
+	// Validates an API key and resolves the user it belongs to
+	ValidateApiKey(context.Context, *ValidateApiKeyRequest) (*ValidateApiKeyResponse, error)
+	// Lists the tools the backend advertises to MCP clients
+	ListTools(context.Context, *ListToolsRequest) (*ListToolsResponse, error)
+	// Executes a backend-advertised tool by name
+	CallTool(context.Context, *CallToolRequest) (*CallToolResponse, error)
 	mustEmbedUnimplementedMcpExtensionServiceServer()
 }
 
@@ -85,6 +219,30 @@ func (UnimplementedMcpExtensionServiceServer) SearchDiary(context.Context, *Sear
 func (UnimplementedMcpExtensionServiceServer) SearchMemory(context.Context, *SearchMemoryRequest) (*SearchMemoryResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SearchMemory not implemented")
 }
+func (UnimplementedMcpExtensionServiceServer) SearchLifeGraph(context.Context, *SearchLifeGraphRequest) (*SearchLifeGraphResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SearchLifeGraph not implemented")
+}
+func (UnimplementedMcpExtensionServiceServer) GetDiary(context.Context, *GetDiaryRequest) (*GetDiaryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDiary not implemented")
+}
+func (UnimplementedMcpExtensionServiceServer) ListDiaries(context.Context, *ListDiariesRequest) (*ListDiariesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDiaries not implemented")
+}
+func (UnimplementedMcpExtensionServiceServer) GetMemory(context.Context, *GetMemoryRequest) (*GetMemoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMemory not implemented")
+}
+func (UnimplementedMcpExtensionServiceServer) ListMemories(context.Context, *ListMemoriesRequest) (*ListMemoriesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListMemories not implemented")
+}
+func (UnimplementedMcpExtensionServiceServer) ValidateApiKey(context.Context, *ValidateApiKeyRequest) (*ValidateApiKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ValidateApiKey not implemented")
+}
+func (UnimplementedMcpExtensionServiceServer) ListTools(context.Context, *ListToolsRequest) (*ListToolsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTools not implemented")
+}
+func (UnimplementedMcpExtensionServiceServer) CallTool(context.Context, *CallToolRequest) (*CallToolResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CallTool not implemented")
+}
 func (UnimplementedMcpExtensionServiceServer) mustEmbedUnimplementedMcpExtensionServiceServer() {}
 func (UnimplementedMcpExtensionServiceServer) testEmbeddedByValue()                             {}
 
@@ -142,6 +300,150 @@ func _McpExtensionService_SearchMemory_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _McpExtensionService_SearchLifeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchLifeGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpExtensionServiceServer).SearchLifeGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpExtensionService_SearchLifeGraph_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpExtensionServiceServer).SearchLifeGraph(ctx, req.(*SearchLifeGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _McpExtensionService_GetDiary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDiaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpExtensionServiceServer).GetDiary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpExtensionService_GetDiary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpExtensionServiceServer).GetDiary(ctx, req.(*GetDiaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _McpExtensionService_ListDiaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDiariesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpExtensionServiceServer).ListDiaries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpExtensionService_ListDiaries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpExtensionServiceServer).ListDiaries(ctx, req.(*ListDiariesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _McpExtensionService_GetMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpExtensionServiceServer).GetMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpExtensionService_GetMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpExtensionServiceServer).GetMemory(ctx, req.(*GetMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _McpExtensionService_ListMemories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMemoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpExtensionServiceServer).ListMemories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpExtensionService_ListMemories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpExtensionServiceServer).ListMemories(ctx, req.(*ListMemoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _McpExtensionService_ValidateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpExtensionServiceServer).ValidateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpExtensionService_ValidateApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpExtensionServiceServer).ValidateApiKey(ctx, req.(*ValidateApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _McpExtensionService_ListTools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListToolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpExtensionServiceServer).ListTools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpExtensionService_ListTools_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpExtensionServiceServer).ListTools(ctx, req.(*ListToolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _McpExtensionService_CallTool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallToolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(McpExtensionServiceServer).CallTool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: McpExtensionService_CallTool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(McpExtensionServiceServer).CallTool(ctx, req.(*CallToolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // McpExtensionService_ServiceDesc is the grpc.ServiceDesc for McpExtensionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -157,6 +459,38 @@ var McpExtensionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchMemory",
 			Handler:    _McpExtensionService_SearchMemory_Handler,
 		},
+		{
+			MethodName: "SearchLifeGraph",
+			Handler:    _McpExtensionService_SearchLifeGraph_Handler,
+		},
+		{
+			MethodName: "GetDiary",
+			Handler:    _McpExtensionService_GetDiary_Handler,
+		},
+		{
+			MethodName: "ListDiaries",
+			Handler:    _McpExtensionService_ListDiaries_Handler,
+		},
+		{
+			MethodName: "GetMemory",
+			Handler:    _McpExtensionService_GetMemory_Handler,
+		},
+		{
+			MethodName: "ListMemories",
+			Handler:    _McpExtensionService_ListMemories_Handler,
+		},
+		{
+			MethodName: "ValidateApiKey",
+			Handler:    _McpExtensionService_ValidateApiKey_Handler,
+		},
+		{
+			MethodName: "ListTools",
+			Handler:    _McpExtensionService_ListTools_Handler,
+		},
+		{
+			MethodName: "CallTool",
+			Handler:    _McpExtensionService_CallTool_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/mcp_extension.proto",
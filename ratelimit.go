@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"mcp/internal/auth"
+	"mcp/internal/ratelimit"
+	"mcp/internal/reqctx"
+	"mcp/pkg/log"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// CodeRateLimited 是调用方触发限流或用完每日配额时返回的 JSON-RPC 错误码
+const CodeRateLimited = -32004
+
+// rateLimitedData 是限流错误的 data 字段，retryAfter 以秒为单位
+type rateLimitedData struct {
+	Limit      string `json:"limit"`
+	Tool       string `json:"tool,omitempty"`
+	RetryAfter int64  `json:"retryAfter"`
+}
+
+func rateLimited(e *ratelimit.ExceededError) error {
+	data, _ := json.Marshal(rateLimitedData{
+		Limit:      e.Limit,
+		Tool:       e.Tool,
+		RetryAfter: int64(math.Ceil(e.RetryAfter.Seconds())),
+	})
+	return &jsonrpc.Error{Code: CodeRateLimited, Message: e.Error(), Data: data}
+}
+
+// rateLimitCaller 返回限流使用的调用方标识：认证后的用户、API Key 摘要或客户端 IP
+func rateLimitCaller(info *reqctx.Info) string {
+	switch {
+	case info.Principal != nil && info.Principal.UserID != "":
+		return "user:" + info.Principal.UserID
+	case info.APIKey != "":
+		return "key:" + auth.HashKey(info.APIKey)
+	default:
+		return "ip:" + info.RemoteIP
+	}
+}
+
+// rateLimitMiddleware 对会访问后端或第三方服务的请求限流
+// 工具调用额外按工具计数并消耗每日配额；限流存储不可用时放行
+func rateLimitMiddleware(limiter *ratelimit.Limiter) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			var tool string
+			switch method {
+			case "tools/call":
+				if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok {
+					tool = params.Name
+				}
+			case "resources/read", "prompts/get":
+			default:
+				return next(ctx, method, req)
+			}
+
+			if err := allowRequest(ctx, limiter, method, tool); err != nil {
+				return nil, err
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// allowRequest 检查当前调用方的一次请求是否在限制之内，超出限制时返回 JSON-RPC 限流错误
+// limiter 为 nil（未开启限流）或限流存储不可用时放行
+func allowRequest(ctx context.Context, limiter *ratelimit.Limiter, method, tool string) error {
+	if limiter == nil {
+		return nil
+	}
+	err := limiter.Allow(ctx, rateLimitCaller(reqctx.From(ctx)), tool)
+	var exceeded *ratelimit.ExceededError
+	if errors.As(err, &exceeded) {
+		return rateLimited(exceeded)
+	}
+	if err != nil {
+		log.Error("限流检查失败", "method", method, "tool", tool, "error", err)
+	}
+	return nil
+}
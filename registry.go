@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"mcp/config"
+	"mcp/internal/ratelimit"
+	"mcp/internal/reqctx"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisteredTool 是注册表中的一个工具条目
+type RegisteredTool struct {
+	Tool    *mcp.Tool
+	Handler InternalToolHandler
+	Enabled bool
+	// Scopes 调用该工具所需的权限范围，调用方必须全部拥有
+	Scopes []string
+
+	// attach 将工具挂载到 SDK server 上，启用工具时调用
+	attach func(*mcp.Server)
+}
+
+// ToolRegistry 是并发安全的工具注册表
+// 工具的增删与启停会同步到 SDK server，由 SDK 向已连接的 session 推送 notifications/tools/list_changed
+type ToolRegistry struct {
+	mu       sync.RWMutex
+	server   *mcp.Server
+	tools    map[string]*RegisteredTool
+	disabled map[string]bool
+	scopes   map[string][]string
+	// limiter 对 Call 发起的调用限流，为 nil 时不限流
+	limiter *ratelimit.Limiter
+}
+
+// NewToolRegistry 创建一个绑定到 SDK server 的注册表
+// cfg.Disabled 中列出的工具在注册时默认处于禁用状态，
+// cfg.Scopes 与声明式工具的 scopes 决定调用各工具所需的权限范围
+func NewToolRegistry(server *mcp.Server, cfg config.ToolsConfig) *ToolRegistry {
+	r := &ToolRegistry{
+		server:   server,
+		tools:    make(map[string]*RegisteredTool),
+		disabled: make(map[string]bool, len(cfg.Disabled)),
+		scopes:   make(map[string][]string, len(cfg.Scopes)),
+	}
+	for _, name := range cfg.Disabled {
+		r.disabled[name] = true
+	}
+	for _, d := range cfg.Declared {
+		if len(d.Scopes) > 0 {
+			r.scopes[d.Name] = d.Scopes
+		}
+	}
+	for _, ts := range cfg.Scopes {
+		r.scopes[ts.Tool] = ts.Scopes
+	}
+	return r
+}
+
+// Register 添加或替换一个工具
+func (r *ToolRegistry) Register(tool *mcp.Tool, handler InternalToolHandler, attach func(*mcp.Server)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := &RegisteredTool{
+		Tool:    tool,
+		Handler: handler,
+		Enabled: !r.disabled[tool.Name],
+		Scopes:  r.scopes[tool.Name],
+		attach:  attach,
+	}
+	if old, ok := r.tools[tool.Name]; ok && old.Enabled && !entry.Enabled {
+		r.server.RemoveTools(tool.Name)
+	}
+	r.tools[tool.Name] = entry
+	if entry.Enabled {
+		entry.attach(r.server)
+	}
+}
+
+// RegisterRaw 注册一个直接处理 JSON 参数的工具，适用于运行时由配置或后端声明的工具
+func (r *ToolRegistry) RegisterRaw(tool *mcp.Tool, handler InternalToolHandler) {
+	r.Register(tool, handler, func(s *mcp.Server) {
+		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handler(ctx, req.Params.Arguments)
+		})
+	})
+}
+
+// Remove 从注册表中移除工具，工具不存在时返回 false
+func (r *ToolRegistry) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.tools[name]
+	if !ok {
+		return false
+	}
+	delete(r.tools, name)
+	if entry.Enabled {
+		r.server.RemoveTools(name)
+	}
+	return true
+}
+
+// Enable 启用工具
+func (r *ToolRegistry) Enable(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.tools[name]
+	if !ok {
+		return fmt.Errorf("tool not found: %s", name)
+	}
+	delete(r.disabled, name)
+	if !entry.Enabled {
+		entry.Enabled = true
+		entry.attach(r.server)
+	}
+	return nil
+}
+
+// Disable 禁用工具，禁用后工具不再出现在 tools/list 中，也无法被调用
+func (r *ToolRegistry) Disable(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.tools[name]
+	if !ok {
+		return fmt.Errorf("tool not found: %s", name)
+	}
+	r.disabled[name] = true
+	if entry.Enabled {
+		entry.Enabled = false
+		r.server.RemoveTools(name)
+	}
+	return nil
+}
+
+// Get 返回工具条目的快照
+func (r *ToolRegistry) Get(name string) (RegisteredTool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.tools[name]
+	if !ok {
+		return RegisteredTool{}, false
+	}
+	return *entry, true
+}
+
+// List 返回按名称排序的全部工具条目快照（包含已禁用的工具）
+func (r *ToolRegistry) List() []RegisteredTool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]RegisteredTool, 0, len(r.tools))
+	for _, entry := range r.tools {
+		list = append(list, *entry)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Tool.Name < list[j].Tool.Name })
+	return list
+}
+
+// Scopes 返回配置中调用该工具所需的权限范围，工具尚未注册时同样适用
+func (r *ToolRegistry) Scopes(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.scopes[name]
+}
+
+// Allowed 判断调用方是否拥有调用该工具所需的全部权限范围
+// 未开启认证时请求中没有调用方身份，不做限制
+func (t RegisteredTool) Allowed(p *reqctx.Principal) bool {
+	if p == nil {
+		return true
+	}
+	for _, scope := range t.Scopes {
+		if !p.HasScope(scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Call 执行一个已启用、调用方有权使用且未超出限流的工具
+func (r *ToolRegistry) Call(ctx context.Context, name string, argsJSON json.RawMessage) (*mcp.CallToolResult, error) {
+	entry, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("tool not found: %s", name)
+	}
+	if !entry.Enabled {
+		return nil, fmt.Errorf("tool disabled: %s", name)
+	}
+	if !entry.Allowed(reqctx.From(ctx).Principal) {
+		return nil, insufficientScope(entry)
+	}
+	// 与经由 tools/call 的调用共用调用方的令牌桶与每日配额
+	if err := allowRequest(ctx, r.limiter, "tools/call", name); err != nil {
+		return nil, err
+	}
+	// 经由其它工具间接调用时，后端路由应以实际执行的工具为准
+	if info := reqctx.From(ctx); info.Tool != name {
+		info = info.Clone()
+		info.Tool = name
+		ctx = reqctx.With(ctx, info)
+	}
+	return entry.Handler(ctx, argsJSON)
+}
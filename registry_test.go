@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"mcp/config"
+	"mcp/internal/ratelimit"
+	"mcp/internal/reqctx"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newTestRegistry 创建绑定到独立 SDK server 的注册表，并注册名为 name 的工具
+func newTestRegistry(cfg config.ToolsConfig, names ...string) (*ToolRegistry, *mcp.Server) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	r := NewToolRegistry(server, cfg)
+	for _, name := range names {
+		r.RegisterRaw(&mcp.Tool{Name: name, InputSchema: map[string]any{"type": "object"}},
+			func(ctx context.Context, argsJSON json.RawMessage) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: reqctx.From(ctx).Tool}}}, nil
+			})
+	}
+	return r, server
+}
+
+func TestRegistryCallIsRateLimited(t *testing.T) {
+	r, _ := newTestRegistry(config.ToolsConfig{}, "web_search", "diarySearch")
+	r.limiter = ratelimit.NewLimiter(config.RateLimitConfig{
+		PerKey: config.BucketConfig{Rate: 100, Burst: 100},
+		Tools: []config.ToolRateLimitConfig{
+			{Tool: "web_search", BucketConfig: config.BucketConfig{Rate: 0.001, Burst: 1}},
+		},
+	}, ratelimit.NewMemoryStore())
+	ctx := reqctx.With(context.Background(), &reqctx.Info{APIKey: "alicekey"})
+
+	// 提示词预填充等内部调用同样消耗工具的令牌桶
+	if _, err := r.Call(ctx, "web_search", nil); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	_, err := r.Call(ctx, "web_search", nil)
+	var wireErr *jsonrpc.Error
+	if !errors.As(err, &wireErr) || wireErr.Code != CodeRateLimited {
+		t.Fatalf("second call err = %v, want code %d", err, CodeRateLimited)
+	}
+
+	// 其它工具与其它调用方不受影响
+	if _, err := r.Call(ctx, "diarySearch", nil); err != nil {
+		t.Fatalf("other tool: %v", err)
+	}
+	bob := reqctx.With(context.Background(), &reqctx.Info{APIKey: "bobkey"})
+	if _, err := r.Call(bob, "web_search", nil); err != nil {
+		t.Fatalf("other caller: %v", err)
+	}
+}
+
+// connectTestClient 通过内存传输连接 server，返回客户端会话与 tools/list_changed 通知的信号
+func connectTestClient(t *testing.T, server *mcp.Server) (*mcp.ClientSession, <-chan struct{}) {
+	t.Helper()
+	ctx := context.Background()
+	changed := make(chan struct{}, 16)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) { changed <- struct{}{} },
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() {
+		cs.Close()
+		ss.Wait()
+	})
+	return cs, changed
+}
+
+// listedTools 返回客户端 tools/list 看到的工具名
+func listedTools(t *testing.T, cs *mcp.ClientSession) []string {
+	t.Helper()
+	res, err := cs.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("tools/list: %v", err)
+	}
+	var names []string
+	for _, tool := range res.Tools {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func waitListChanged(t *testing.T, changed <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("no notifications/tools/list_changed")
+	}
+}
+
+func TestRegistryLifecycle(t *testing.T) {
+	r, server := newTestRegistry(config.ToolsConfig{Disabled: []string{"memorySearch"}}, "diarySearch", "memorySearch")
+	cs, changed := connectTestClient(t, server)
+	ctx := context.Background()
+
+	// 配置中禁用的工具已注册但不对客户端可见，也不能调用
+	if entry, ok := r.Get("memorySearch"); !ok || entry.Enabled {
+		t.Fatalf("memorySearch = %+v, %v; want registered and disabled", entry, ok)
+	}
+	if got := listedTools(t, cs); !slices.Equal(got, []string{"diarySearch"}) {
+		t.Fatalf("tools/list = %v", got)
+	}
+	if _, err := r.Call(ctx, "memorySearch", nil); err == nil {
+		t.Fatal("call of disabled tool succeeded")
+	}
+
+	steps := []struct {
+		name   string
+		change func() error
+		want   []string
+	}{
+		{"enable", func() error { return r.Enable("memorySearch") }, []string{"diarySearch", "memorySearch"}},
+		{"disable", func() error { return r.Disable("diarySearch") }, []string{"memorySearch"}},
+		{"register", func() error {
+			r.RegisterRaw(&mcp.Tool{Name: "web_search", InputSchema: map[string]any{"type": "object"}}, nil)
+			return nil
+		}, []string{"memorySearch", "web_search"}},
+		{"remove", func() error {
+			if !r.Remove("web_search") {
+				return errors.New("web_search not found")
+			}
+			return nil
+		}, []string{"memorySearch"}},
+	}
+	for _, step := range steps {
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		waitListChanged(t, changed)
+		if got := listedTools(t, cs); !slices.Equal(got, step.want) {
+			t.Fatalf("after %s: tools/list = %v, want %v", step.name, got, step.want)
+		}
+	}
+
+	// 注册表仍列出已禁用的工具，未注册的工具无法移除或启用
+	if _, err := r.Call(ctx, "diarySearch", nil); err == nil {
+		t.Fatal("call of disabled tool succeeded")
+	}
+	if got := len(r.List()); got != 2 {
+		t.Fatalf("List() has %d tools, want 2", got)
+	}
+	if r.Remove("web_search") {
+		t.Fatal("Remove of unknown tool returned true")
+	}
+	if err := r.Enable("web_search"); err == nil {
+		t.Fatal("Enable of unknown tool succeeded")
+	}
+}
+
+func TestRegistryReRegisterKeepsDisabledState(t *testing.T) {
+	r, server := newTestRegistry(config.ToolsConfig{}, "diarySearch")
+	cs, changed := connectTestClient(t, server)
+
+	if err := r.Disable("diarySearch"); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	waitListChanged(t, changed)
+
+	// 后端重新声明工具时不会绕过管理员的禁用
+	r.RegisterRaw(&mcp.Tool{Name: "diarySearch", InputSchema: map[string]any{"type": "object"}}, nil)
+	if entry, _ := r.Get("diarySearch"); entry.Enabled {
+		t.Fatal("re-registered tool is enabled")
+	}
+	if got := listedTools(t, cs); len(got) != 0 {
+		t.Fatalf("tools/list = %v, want none", got)
+	}
+}
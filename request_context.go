@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"mcp/internal/reqctx"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// requestContextMiddleware 为每个 MCP 请求补全调用方、请求 ID、会话 ID、客户端信息、工具名称与截止时间
+// 会话的 context 来自建立会话的请求，因此调用方与请求 ID、trace 取自当前消息所在 HTTP 请求的 RequestExtra；
+// 工具调用、资源读取与提示词获取受 timeout 限制，0 表示不限制。
+// 需要位于最外层，使其它中间件与处理函数都能读取到完整的请求信息
+func requestContextMiddleware(timeout time.Duration) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			info := reqctx.From(ctx).Clone()
+			if extra := req.GetExtra(); extra != nil {
+				if id, ok := reqctx.IdentityFromTokenInfo(extra.TokenInfo); ok {
+					info.APIKey, info.Principal, info.RemoteIP = id.APIKey, id.Principal, id.RemoteIP
+				}
+				if extra.Header != nil {
+					info.RequestID = extra.Header.Get(reqctx.RequestIDHeader)
+					info.TraceParent = extra.Header.Get(reqctx.TraceParentHeader)
+					info.TraceState = extra.Header.Get(reqctx.TraceStateHeader)
+				}
+			}
+			// 没有经过 RequestContext 的消息（例如服务端内部发起的调用）另行分配请求 ID 与 trace
+			if info.RequestID == "" {
+				info.RequestID = reqctx.NewRequestID()
+			}
+			if !reqctx.ValidTraceParent(info.TraceParent) {
+				info.TraceParent, info.TraceState = reqctx.NewTraceParent(), ""
+			}
+			if ss, ok := req.GetSession().(*mcp.ServerSession); ok {
+				info.SessionID = ss.ID()
+				if params := ss.InitializeParams(); params != nil && params.ClientInfo != nil {
+					info.Client = reqctx.ClientInfo{Name: params.ClientInfo.Name, Version: params.ClientInfo.Version}
+				}
+			}
+			if p, ok := req.GetParams().(*mcp.InitializeParams); ok && p.ClientInfo != nil {
+				info.Client = reqctx.ClientInfo{Name: p.ClientInfo.Name, Version: p.ClientInfo.Version}
+			}
+			if p, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok {
+				info.Tool = p.Name
+			}
+			switch method {
+			case "tools/call", "resources/read", "prompts/get":
+				if timeout > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithTimeout(ctx, timeout)
+					defer cancel()
+				}
+			}
+			info.Deadline = time.Time{}
+			if deadline, ok := ctx.Deadline(); ok {
+				info.Deadline = deadline
+			}
+			return next(reqctx.With(ctx, info), method, req)
+		}
+	}
+}
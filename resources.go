@@ -0,0 +1,282 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp/config"
+	"mcp/internal/reqctx"
+	"mcp/internal/resources"
+	"mcp/pkg/log"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resourceSubscription 是某个 session 对资源的一次订阅，记录读取资源所需的调用方身份
+type resourceSubscription struct {
+	// subscriber 标识订阅的调用方（见 reqctx.SessionUser），同一调用方的订阅共用一次轮询
+	subscriber string
+	apiKey     string
+	principal  *reqctx.Principal
+}
+
+// watchKey 是一次轮询的对象：同一资源对不同调用方可能有不同的内容或可见性
+type watchKey struct {
+	subscriber string
+	uri        string
+}
+
+// resourceWatch 记录被订阅的资源及其最近一次内容摘要，用于检测变化并发送 notifications/resources/updated
+type resourceWatch struct {
+	mu      sync.Mutex
+	subs    map[string]map[*mcp.ServerSession]resourceSubscription // uri -> session -> 订阅
+	digests map[watchKey]string                                    // (调用方, uri) -> 内容摘要
+	// notify 是正在发送的变更通知的接收方，见 resourceUpdatedFilter
+	notify map[string]map[*mcp.ServerSession]bool
+}
+
+// registerResources 注册日记与记忆资源模板，并为 resources/list 补充后端列出的资源
+func registerResources(s *MCPServer, cfg config.ResourcesConfig) {
+	s.resources = []resources.Resource{
+		resources.NewDiaryResource(),
+		resources.NewMemoryResource(),
+	}
+	for _, r := range s.resources {
+		s.Server.AddResourceTemplate(r.GetTemplateDef(), r.Read)
+	}
+	s.Server.AddReceivingMiddleware(listResourcesMiddleware(s, cfg.ListLimit), resourceScopeMiddleware(s))
+	s.Server.AddSendingMiddleware(resourceUpdatedFilter(&s.resourceWatch))
+}
+
+// resourceUpdatedFilter 使 notifications/resources/updated 只发送给内容发生变化的调用方的 session
+// SDK 的 Server.ResourceUpdated 会通知该资源的全部订阅者，而资源内容因调用方而异
+func resourceUpdatedFilter(w *resourceWatch) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "notifications/resources/updated" {
+				return next(ctx, method, req)
+			}
+			params, ok := req.GetParams().(*mcp.ResourceUpdatedNotificationParams)
+			session, _ := req.GetSession().(*mcp.ServerSession)
+			w.mu.Lock()
+			notify := ok && w.notify[params.URI][session]
+			w.mu.Unlock()
+			if !notify {
+				return nil, nil
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// listResourcesMiddleware 在 SDK 返回的静态资源之后追加当前用户最近的日记和记忆
+// 日记与记忆因用户而异，无法预先通过 AddResource 注册；调用方缺少权限范围的资源类型不会列出
+func listResourcesMiddleware(s *MCPServer, limit int) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			if err != nil || method != "resources/list" {
+				return res, err
+			}
+			list, ok := res.(*mcp.ListResourcesResult)
+			if !ok {
+				return res, err
+			}
+			// 动态资源只出现在第一页
+			if params, ok := req.GetParams().(*mcp.ListResourcesParams); ok && params != nil && params.Cursor != "" {
+				return res, err
+			}
+			principal := reqctx.From(ctx).Principal
+			for _, r := range s.resources {
+				if !s.resourceAllowed(r, principal) {
+					continue
+				}
+				items, err := r.List(ctx, int32(limit))
+				if err != nil {
+					log.Warn("列出资源失败", "template", r.GetTemplateDef().URITemplate, "error", err)
+					continue
+				}
+				list.Resources = append(list.Resources, items...)
+			}
+			return list, nil
+		}
+	}
+}
+
+// findResource 按 URI 的 scheme 返回对应的资源类型
+func (s *MCPServer) findResource(uri string) (resources.Resource, bool) {
+	for _, r := range s.resources {
+		scheme, _, _ := strings.Cut(r.GetTemplateDef().URITemplate, "://")
+		if strings.HasPrefix(uri, scheme+"://") {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+func isResourceNotFound(err error) bool {
+	var wireErr *jsonrpc.Error
+	return errors.As(err, &wireErr) && wireErr.Code == mcp.CodeResourceNotFound
+}
+
+// readResource 以订阅者的身份读取资源并返回内容摘要
+func (s *MCPServer) readResource(ctx context.Context, uri string, sub resourceSubscription) (string, error) {
+	r, ok := s.findResource(uri)
+	if !ok {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	info := reqctx.From(ctx).Clone()
+	info.APIKey = sub.apiKey
+	info.Principal = sub.principal
+	ctx = reqctx.With(ctx, info)
+	res, err := r.Read(ctx, &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: uri}})
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, c := range res.Contents {
+		fmt.Fprintf(h, "%s\x00%s\x00", c.Text, c.Blob)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// subscribeResource 处理 resources/subscribe，订阅前先读取一次资源以校验其存在并记录初始摘要
+func (s *MCPServer) subscribeResource(ctx context.Context, req *mcp.SubscribeRequest) error {
+	uri := req.Params.URI
+	info := reqctx.From(ctx)
+	sub := resourceSubscription{
+		subscriber: reqctx.SessionUser(info),
+		apiKey:     info.APIKey,
+		principal:  info.Principal,
+	}
+	digest, err := s.readResource(ctx, uri, sub)
+	if err != nil {
+		return err
+	}
+
+	w := &s.resourceWatch
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.subs == nil {
+		w.subs = make(map[string]map[*mcp.ServerSession]resourceSubscription)
+		w.digests = make(map[watchKey]string)
+	}
+	if w.subs[uri] == nil {
+		w.subs[uri] = make(map[*mcp.ServerSession]resourceSubscription)
+	}
+	key := watchKey{subscriber: sub.subscriber, uri: uri}
+	if _, ok := w.digests[key]; !ok {
+		w.digests[key] = digest
+	}
+	w.subs[uri][req.Session] = sub
+	return nil
+}
+
+// unsubscribeResource 处理 resources/unsubscribe
+func (s *MCPServer) unsubscribeResource(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	w := &s.resourceWatch
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.remove(req.Params.URI, req.Session)
+	return nil
+}
+
+// remove 移除 session 的订阅，该调用方不再有 session 订阅此资源时一并停止轮询
+func (w *resourceWatch) remove(uri string, session *mcp.ServerSession) {
+	sub, ok := w.subs[uri][session]
+	if !ok {
+		return
+	}
+	delete(w.subs[uri], session)
+	for _, other := range w.subs[uri] {
+		if other.subscriber == sub.subscriber {
+			return
+		}
+	}
+	delete(w.digests, watchKey{subscriber: sub.subscriber, uri: uri})
+	if len(w.subs[uri]) == 0 {
+		delete(w.subs, uri)
+	}
+}
+
+// CheckSubscribedResources 以各订阅者的身份重新读取被订阅的资源，
+// 内容变化或被删除时只通知该订阅者的 session；已断开的 session 的订阅会在此一并清理
+func (s *MCPServer) CheckSubscribedResources(ctx context.Context) {
+	live := make(map[*mcp.ServerSession]bool)
+	for session := range s.Server.Sessions() {
+		live[session] = true
+	}
+
+	w := &s.resourceWatch
+	w.mu.Lock()
+	targets := make(map[watchKey]resourceSubscription, len(w.digests))
+	for uri, sessions := range w.subs {
+		for session, sub := range sessions {
+			if !live[session] {
+				w.remove(uri, session)
+				continue
+			}
+			targets[watchKey{subscriber: sub.subscriber, uri: uri}] = sub
+		}
+	}
+	w.mu.Unlock()
+
+	notify := make(map[string]map[*mcp.ServerSession]bool)
+	for key, sub := range targets {
+		digest, err := s.readResource(ctx, key.uri, sub)
+		if err != nil && !isResourceNotFound(err) {
+			log.Warn("检查订阅资源失败", "uri", key.uri, "error", err)
+			continue
+		}
+
+		w.mu.Lock()
+		old, ok := w.digests[key]
+		if ok && old != digest {
+			w.digests[key] = digest
+			for session, other := range w.subs[key.uri] {
+				if other.subscriber == key.subscriber {
+					if notify[key.uri] == nil {
+						notify[key.uri] = make(map[*mcp.ServerSession]bool)
+					}
+					notify[key.uri][session] = true
+				}
+			}
+		}
+		w.mu.Unlock()
+	}
+
+	for uri, sessions := range notify {
+		log.Info("订阅的资源已变化", "uri", uri, "sessions", len(sessions))
+		w.mu.Lock()
+		w.notify = map[string]map[*mcp.ServerSession]bool{uri: sessions}
+		w.mu.Unlock()
+		// ResourceUpdated 同步发送，接收方由 resourceUpdatedFilter 按 w.notify 过滤
+		s.Server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+		w.mu.Lock()
+		w.notify = nil
+		w.mu.Unlock()
+	}
+}
+
+// RunResourceWatch 按固定间隔检查被订阅的资源，直到 ctx 被取消
+func (s *MCPServer) RunResourceWatch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			s.CheckSubscribedResources(checkCtx)
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -4,72 +4,123 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"mcp/config"
 	"mcp/internal/grpc"
+	"mcp/internal/prompts"
+	"mcp/internal/ratelimit"
+	"mcp/internal/resources"
 	ext_tools "mcp/internal/tools"
+	"mcp/pkg/log"
 	"mcp/tools"
 
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 type InternalToolHandler func(ctx context.Context, argsJSON json.RawMessage) (*mcp.CallToolResult, error)
 
-type RegisteredTool struct {
-	Tool    *mcp.Tool
-	Handler InternalToolHandler
-}
-
 type MCPServer struct {
 	Server *mcp.Server
 	Config *config.MCPConfig
-	Tools  map[string]RegisteredTool
+	Tools  *ToolRegistry
+
+	backendSync   backendToolSync
+	resources     []resources.Resource
+	resourceWatch resourceWatch
+	lifecycle     lifecycle
 }
 
 // RegisterTool 将工具同时注册到 MCP server SDK 和内部注册表中
 func RegisterTool[T any](s *MCPServer, tool *mcp.Tool, handler func(context.Context, *mcp.CallToolRequest, T) (*mcp.CallToolResult, any, error)) {
-	// 向 SDK 注册
-	mcp.AddTool(s.Server, tool, handler)
-
-	// 内部注册
-	if s.Tools == nil {
-		s.Tools = make(map[string]RegisteredTool)
-	}
-	s.Tools[tool.Name] = RegisteredTool{
-		Tool: tool,
-		Handler: func(ctx context.Context, argsJSON json.RawMessage) (*mcp.CallToolResult, error) {
-			var args T
+	internal := func(ctx context.Context, argsJSON json.RawMessage) (*mcp.CallToolResult, error) {
+		var args T
+		if len(argsJSON) > 0 {
 			if err := json.Unmarshal(argsJSON, &args); err != nil {
 				return nil, fmt.Errorf("failed to unmarshal args: %w", err)
 			}
-			// 我们对 CallToolRequest 传 nil，因为这是一个内部调用
-			res, _, err := handler(ctx, nil, args)
-			return res, err
-		},
+		}
+		// 我们对 CallToolRequest 传 nil，因为这是一个内部调用
+		res, out, err := handler(ctx, nil, args)
+		return toolResult(res, out, err)
+	}
+
+	// 向 SDK 注册的闭包保留了泛型处理函数，使 SDK 能够基于 schema 校验入参
+	s.Tools.Register(tool, internal, func(srv *mcp.Server) {
+		mcp.AddTool(srv, tool, handler)
+	})
+}
+
+// toolResult 按照 SDK 的约定组装内部调用的结果，保证与经由协议返回给客户端的内容一致：
+// 普通错误转为 IsError 结果，结构化输出写入 StructuredContent，原有的各类 Content 原样保留
+func toolResult(res *mcp.CallToolResult, out any, err error) (*mcp.CallToolResult, error) {
+	if err != nil {
+		if wireErr, ok := err.(*jsonrpc.Error); ok {
+			return nil, wireErr
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+			IsError: true,
+		}, nil
 	}
+	if res == nil {
+		res = &mcp.CallToolResult{}
+	}
+	if out != nil && res.StructuredContent == nil {
+		outJSON, err := json.Marshal(out)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal output: %w", err)
+		}
+		res.StructuredContent = json.RawMessage(outJSON)
+		if res.Content == nil {
+			res.Content = []mcp.Content{&mcp.TextContent{Text: string(outJSON)}}
+		}
+	}
+	if res.Content == nil {
+		res.Content = []mcp.Content{}
+	}
+	return res, nil
 }
 
-func NewMCPServer(cfg *config.MCPConfig) *MCPServer {
+// NewMCPServer 创建 MCP 服务并注册全部工具、资源与提示词
+// ctx 控制后台任务（资源变更检查、后端工具同步）的生命周期，取消后任务退出
+func NewMCPServer(ctx context.Context, cfg *config.MCPConfig) *MCPServer {
+	mcpSrv := &MCPServer{Config: cfg}
+
+	var opts *mcp.ServerOptions
+	if cfg.Resources.Enabled {
+		opts = &mcp.ServerOptions{
+			SubscribeHandler:   mcpSrv.subscribeResource,
+			UnsubscribeHandler: mcpSrv.unsubscribeResource,
+		}
+	}
 	s := mcp.NewServer(&mcp.Implementation{
 		Name:    "ai-ability-mcp",
 		Version: "1.0.0",
-	}, nil)
-
-	mcpSrv := &MCPServer{
-		Server: s,
-		Config: cfg,
-		Tools:  make(map[string]RegisteredTool),
+	}, opts)
+
+	mcpSrv.Server = s
+	mcpSrv.Tools = NewToolRegistry(s, cfg.Tools)
+	// 后添加的中间件先执行：先校验权限范围，被拒绝的调用不消耗限流额度
+	if cfg.RateLimit.Enabled {
+		limiter := ratelimit.NewLimiter(cfg.RateLimit, ratelimit.NewMemoryStore())
+		s.AddReceivingMiddleware(rateLimitMiddleware(limiter))
+		// 提示词预填充等经由注册表的内部调用不经过接收中间件，由注册表自行限流
+		mcpSrv.Tools.limiter = limiter
 	}
+	s.AddReceivingMiddleware(toolScopeMiddleware(mcpSrv.Tools))
 
 	// 注册工具
-	if cfg.Search.Provider != "" {
+	if len(cfg.Search.Providers) > 0 {
 		searchTool := tools.NewSearchTool(cfg.Search)
 		RegisterTool(mcpSrv, searchTool.GetToolDef(), searchTool.Execute)
 	}
 
-	// 初始化后端 gRPC 连接
-	grpcTarget := cfg.Grpc.BackendTarget
-	grpc.InitClient(grpcTarget)
+	// 初始化后端 gRPC 连接，失败时后端工具返回错误而不影响其它能力
+	if err := grpc.InitClient(cfg.Grpc); err != nil {
+		log.Error("无法初始化后端 gRPC 连接", "target", cfg.Grpc.BackendTarget, "error", err)
+	}
 
 	// 注册扩展工具 (Extension Tools)
 	diarySearchTool := ext_tools.NewSearchDiaryTool()
@@ -78,23 +129,80 @@ func NewMCPServer(cfg *config.MCPConfig) *MCPServer {
 	memorySearchTool := ext_tools.NewSearchMemoryTool()
 	RegisterTool(mcpSrv, memorySearchTool.GetToolDef(), memorySearchTool.Execute)
 
+	lifeGraphTool := ext_tools.NewSearchLifeGraphTool()
+	RegisterTool(mcpSrv, lifeGraphTool.GetToolDef(), lifeGraphTool.Execute)
+
+	// 注册日记与记忆资源
+	if cfg.Resources.Enabled {
+		registerResources(mcpSrv, cfg.Resources)
+		go mcpSrv.RunResourceWatch(ctx, cfg.Resources.PollInterval)
+	}
+
+	// 通过 gRPC server reflection 自动暴露白名单内的后端方法
+	if cfg.Grpc.Reflection.Enabled {
+		registerReflectedTools(mcpSrv, cfg.Grpc.Reflection)
+	}
+
+	// 注册配置文件中声明的工具
+	for _, declared := range cfg.Tools.Declared {
+		declaredCtx, cancel := context.WithTimeout(ctx, declared.Backend.Timeout)
+		declaredTool, err := ext_tools.NewDeclaredTool(declaredCtx, declared)
+		cancel()
+		if err != nil {
+			log.Error("无法注册声明式工具", "tool", declared.Name, "error", err)
+			continue
+		}
+		mcpSrv.Tools.RegisterRaw(declaredTool.GetToolDef(), declaredTool.Handle)
+	}
+
+	// 注册提示词模板；配置的目录不存在时不会报错，这里提示以免模板被静默忽略
+	if dir := cfg.Prompts.Dir; dir != "" {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			log.Warn("提示词目录不存在，只加载配置文件中定义的模板", "dir", dir)
+		}
+	}
+	for _, promptCfg := range cfg.Prompts.Templates {
+		prompt, err := prompts.NewPromptTemplate(promptCfg, mcpSrv.CallTool)
+		if err != nil {
+			log.Error("无法注册提示词", "prompt", promptCfg.Name, "error", err)
+			continue
+		}
+		s.AddPrompt(prompt.GetPromptDef(), prompt.Handle)
+	}
+
+	// 定期从后端同步其声明的工具
+	if cfg.Grpc.ToolSync.Enabled {
+		go mcpSrv.RunBackendToolSync(ctx, cfg.Grpc.ToolSync.Interval)
+	}
+
+	// 最后添加，位于全部接收中间件的最外层
+	s.AddReceivingMiddleware(requestContextMiddleware(cfg.Server.RequestTimeout), inflightMiddleware(&mcpSrv.lifecycle))
+
 	return mcpSrv
 }
 
-// CallTool 根据工具名称执行已注册的工具
-func (s *MCPServer) CallTool(ctx context.Context, name string, argsJSON []byte) (*mcp.CallToolResult, error) {
-	tool, ok := s.Tools[name]
-	if !ok {
-		return nil, fmt.Errorf("tool not found: %s", name)
+// registerReflectedTools 发现后端方法并注册为工具，已存在的同名工具不会被覆盖
+func registerReflectedTools(s *MCPServer, cfg config.ReflectionConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	methods, err := grpc.DiscoverMethods(ctx, cfg.Allow)
+	if err != nil {
+		log.Error("gRPC 反射发现后端方法失败", "target", s.Config.Grpc.BackendTarget, "error", err)
+		return
+	}
+	for _, md := range methods {
+		reflectedTool := ext_tools.NewReflectedTool(md, cfg.ToolPrefix)
+		if _, exists := s.Tools.Get(reflectedTool.Name); exists {
+			log.Warn("反射工具与已有工具重名，已跳过", "tool", reflectedTool.Name, "method", md.FullName())
+			continue
+		}
+		s.Tools.RegisterRaw(reflectedTool.GetToolDef(), reflectedTool.Handle)
+		log.Info("已注册反射工具", "tool", reflectedTool.Name, "method", md.FullName())
 	}
-	return tool.Handler(ctx, argsJSON)
 }
 
-// GetTools 返回所有已注册工具的定义
-func (s *MCPServer) GetTools() []*mcp.Tool {
-	tools := make([]*mcp.Tool, 0, len(s.Tools))
-	for _, t := range s.Tools {
-		tools = append(tools, t.Tool)
-	}
-	return tools
+// CallTool 根据工具名称执行已注册的工具
+func (s *MCPServer) CallTool(ctx context.Context, name string, argsJSON []byte) (*mcp.CallToolResult, error) {
+	return s.Tools.Call(ctx, name, argsJSON)
 }
@@ -16,35 +16,42 @@ type SearchArgs struct {
 	Query string `json:"query" jsonschema:"required"`
 }
 
+// SearchOutput 是 web_search 的结构化输出 (structuredContent)
+type SearchOutput struct {
+	Query string `json:"query"`
+	// Provider 实际作答的搜索提供者，联合搜索时为 federated
+	Provider string `json:"provider"`
+	// Providers 联合搜索时参与合并的提供者
+	Providers []search_utils.SearchType       `json:"providers,omitempty"`
+	Results   []search_utils.SearchResultItem `json:"results"`
+}
+
 // SearchTool 实现了网络搜索工具
 type SearchTool struct {
-	Config   config.SearchConfig
-	Provider search_utils.Provider
+	Config  config.SearchConfig
+	Service *search_utils.Service
 }
 
-// NewSearchTool 创建一个新的 SearchTool
+// NewSearchTool 创建一个新的 SearchTool，按 search.providers 的顺序组成提供者链
 func NewSearchTool(cfg config.SearchConfig) *SearchTool {
-	var provider search_utils.Provider
-	switch strings.ToLower(cfg.Provider) {
-	case "google":
-		provider = &search_utils.GoogleProvider{APIKey: cfg.APIKey, CX: cfg.CX}
-	case "serper":
-		provider = &search_utils.SerperProvider{APIKey: cfg.APIKey}
-	case "bocha":
-		provider = &search_utils.BochaProvider{APIKey: cfg.APIKey}
-	default:
-		// 默认为 Tavily
-		provider = &search_utils.TavilyProvider{APIKey: cfg.APIKey}
+	providers := make([]search_utils.ProviderConfig, 0, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		providers = append(providers, search_utils.ProviderConfig{
+			Type:   search_utils.SearchType(p.Name),
+			APIKey: p.APIKey,
+			CX:     p.CX,
+		})
 	}
-	return &SearchTool{Config: cfg, Provider: provider}
+	service := search_utils.NewService(&search_utils.Config{
+		Providers:      providers,
+		CircuitBreaker: cfg.CircuitBreaker,
+	})
+	return &SearchTool{Config: cfg, Service: service}
 }
 
 // GetToolDef 返回 MCP 工具的具体定义
 func (t *SearchTool) GetToolDef() *mcp.Tool {
-	desc := fmt.Sprintf("使用 %s 在互联网上搜索实时信息。当你需要查找当前事件、事实或训练数据中不存在的最新信息时使用此工具。", t.Config.Provider)
-	if t.Config.Provider == "" {
-		desc = "使用 Google 搜索在互联网上搜索实时信息。当你需要查找当前事件、事实或训练数据中不存在的最新信息时使用此工具。"
-	}
+	desc := fmt.Sprintf("使用 %s 在互联网上搜索实时信息。当你需要查找当前事件、事实或训练数据中不存在的最新信息时使用此工具。", joinProviders(t.Service.GetAvailableProviders()))
 
 	return &mcp.Tool{
 		Name:        "web_search",
@@ -66,19 +73,20 @@ func (t *SearchTool) getInputSchema() map[string]interface{} {
 	}
 }
 
-// Register 将工具注册到 MCP Server
-// 已弃用：请直接使用 GetToolDef 并在 server 中调用 RegisterTool
-func (t *SearchTool) Register(s *mcp.Server) {
-	mcp.AddTool(s, t.GetToolDef(), t.Execute)
-}
-
 // Execute 真正执行搜索逻辑
 func (t *SearchTool) Execute(ctx context.Context, req *mcp.CallToolRequest, args SearchArgs) (*mcp.CallToolResult, any, error) {
 	options := &search_utils.SearchOptions{
 		MaxResults: 2,
 	}
 
-	items, err := t.Provider.Search(ctx, args.Query, options)
+	var res *search_utils.SearchResult
+	var err error
+	if t.Config.Mode == "federated" {
+		options.MaxResults = t.Config.Federated.MaxResults
+		res, err = t.Service.SearchFederated(ctx, args.Query, options, t.Config.Federated.Timeout)
+	} else {
+		res, err = t.Service.SearchChain(ctx, args.Query, options)
+	}
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -88,7 +96,13 @@ func (t *SearchTool) Execute(ctx context.Context, req *mcp.CallToolRequest, args
 		}, nil, nil
 	}
 
+	items := res.Items
 	var sb strings.Builder
+	if len(res.Providers) > 0 {
+		fmt.Fprintf(&sb, "搜索来源: %s（联合搜索）\n\n", joinProviders(res.Providers))
+	} else {
+		fmt.Fprintf(&sb, "搜索来源: %s\n\n", res.Type)
+	}
 	if len(items) > 0 && items[0].Answer != "" {
 		sb.WriteString("AI 回答: ")
 		sb.WriteString(items[0].Answer)
@@ -98,16 +112,44 @@ func (t *SearchTool) Execute(ctx context.Context, req *mcp.CallToolRequest, args
 	for i, item := range items {
 		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, item.Title))
 		sb.WriteString(fmt.Sprintf("   URL: %s\n", item.URL))
+		if len(item.Providers) > 0 {
+			sb.WriteString(fmt.Sprintf("   来源: %s\n", joinProviders(item.Providers)))
+		}
 		sb.WriteString(fmt.Sprintf("   %s\n\n", item.Content))
 	}
 
-	if sb.Len() == 0 {
+	if len(items) == 0 {
 		sb.WriteString("未找到任何结果。")
 	}
 
+	content := []mcp.Content{
+		&mcp.TextContent{Text: sb.String()},
+	}
+	// 搜索结果中的图片以 resource_link 形式返回，由客户端决定是否拉取
+	for _, item := range items {
+		for _, img := range item.Images {
+			content = append(content, &mcp.ResourceLink{
+				URI:  img,
+				Name: img,
+			})
+		}
+	}
+
+	if items == nil {
+		items = []search_utils.SearchResultItem{}
+	}
+
 	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: sb.String()},
-		},
+		Content:           content,
+		StructuredContent: SearchOutput{Query: args.Query, Provider: string(res.Type), Providers: res.Providers, Results: items},
 	}, nil, nil
 }
+
+// joinProviders 以顿号连接提供者名称
+func joinProviders(providers []search_utils.SearchType) string {
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, string(p))
+	}
+	return strings.Join(names, "、")
+}
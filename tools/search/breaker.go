@@ -0,0 +1,38 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"mcp/internal/breaker"
+)
+
+// breakerProvider 为 Provider 加上熔断器，提供者持续出错或变慢时直接返回 *breaker.OpenError
+type breakerProvider struct {
+	Provider
+	breaker *breaker.Breaker
+}
+
+// WithBreaker 使用熔断器包装 Provider，b 为 nil（未开启熔断）时原样返回
+func WithBreaker(p Provider, b *breaker.Breaker) Provider {
+	if b == nil {
+		return p
+	}
+	return &breakerProvider{Provider: p, breaker: b}
+}
+
+func (p *breakerProvider) Search(ctx context.Context, query string, options *SearchOptions) ([]SearchResultItem, error) {
+	call, err := p.breaker.Allow()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	items, err := p.Provider.Search(ctx, query, options)
+	// 调用方取消或到达调用方的截止时间（例如联合搜索的超时）不说明提供者有问题，不计入熔断统计
+	if ctx.Err() != nil {
+		call.Release()
+		return items, err
+	}
+	call.Done(err != nil, time.Since(start))
+	return items, err
+}
@@ -0,0 +1,157 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp/pkg/log"
+)
+
+// SearchTypeFederated 表示结果由多个提供者的结果合并而成
+const SearchTypeFederated SearchType = "federated"
+
+// rrfK 是倒数排名融合（Reciprocal Rank Fusion）的平滑常数，取论文中的常用值
+const rrfK = 60
+
+// SearchFederated 并行查询全部提供者，按规范化后的 URL 去重，并以倒数排名融合合并为一个列表
+// 超过 timeout 仍未返回的提供者不参与合并；合并后的结果数不超过 options.MaxResults。
+// 结果的 Type 为 SearchTypeFederated，Providers 为参与合并的提供者（按提供者链的顺序）
+func (s *Service) SearchFederated(ctx context.Context, query string, options *SearchOptions, timeout time.Duration) (*SearchResult, error) {
+	if len(s.chain) == 0 {
+		return nil, fmt.Errorf("no search provider configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make([]*SearchResult, len(s.chain))
+	errs := make([]error, len(s.chain))
+	var wg sync.WaitGroup
+	for i, t := range s.chain {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = s.Search(ctx, t, query, options)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", t, errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	var answered []*SearchResult
+	for i, res := range results {
+		if errs[i] != nil {
+			log.Warn("联合搜索中的提供者调用失败", "provider", s.chain[i], "error", errs[i])
+			continue
+		}
+		answered = append(answered, res)
+	}
+	if len(answered) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	limit := 0
+	if options != nil {
+		limit = options.MaxResults
+	}
+	merged := &SearchResult{
+		Type:  SearchTypeFederated,
+		Query: query,
+		Items: fuseRankings(answered, limit),
+	}
+	for _, res := range answered {
+		merged.Providers = append(merged.Providers, res.Type)
+	}
+	return merged, nil
+}
+
+// fuseRankings 以倒数排名融合合并多个提供者的结果：每条结果的得分为其在各提供者中 1/(rrfK+排名) 之和
+// 同一 URL 保留排名最靠前的那一条；得分相同时按提供者链与原始排名的顺序；limit 为 0 表示不截断。
+// 各提供者的 AI 回答只保留第一个，放在合并后的第一条结果上
+func fuseRankings(results []*SearchResult, limit int) []SearchResultItem {
+	type fused struct {
+		item      SearchResultItem
+		score     float64
+		bestRank  int
+		providers []SearchType
+	}
+	byURL := make(map[string]*fused)
+	var order []*fused
+	answer := ""
+
+	for _, res := range results {
+		for rank, item := range res.Items {
+			if answer == "" {
+				answer = item.Answer
+			}
+			key := normalizeURL(item.URL)
+			if key == "" {
+				// 没有 URL 的结果无法去重，各自保留
+				key = fmt.Sprintf("%s#%d", res.Type, rank)
+			}
+			f, ok := byURL[key]
+			if !ok {
+				f = &fused{item: item, bestRank: rank}
+				byURL[key] = f
+				order = append(order, f)
+			} else if rank < f.bestRank {
+				f.item, f.bestRank = item, rank
+			}
+			// 同一提供者返回重复的 URL 时只计一次
+			if len(f.providers) == 0 || f.providers[len(f.providers)-1] != res.Type {
+				f.score += 1 / float64(rrfK+rank+1)
+				f.providers = append(f.providers, res.Type)
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].score > order[j].score })
+	if limit > 0 && len(order) > limit {
+		order = order[:limit]
+	}
+
+	items := make([]SearchResultItem, 0, len(order))
+	for _, f := range order {
+		item := f.item
+		item.Answer = ""
+		item.Providers = f.providers
+		items = append(items, item)
+	}
+	if len(items) > 0 {
+		items[0].Answer = answer
+	}
+	return items
+}
+
+// normalizeURL 返回用于去重的 URL：忽略协议、www. 前缀、默认端口、片段、末尾斜杠与 utm_ 跟踪参数，
+// 并对查询参数排序；无法解析时原样返回
+func normalizeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	query := u.Query()
+	for k := range query {
+		if strings.HasPrefix(strings.ToLower(k), "utm_") {
+			query.Del(k)
+		}
+	}
+	key := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	// Encode 按键排序
+	if q := query.Encode(); q != "" {
+		key += "?" + q
+	}
+	return key
+}
@@ -0,0 +1,171 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"https://example.com/page", "http://example.com/page", true},
+		{"https://www.example.com/page", "https://example.com/page", true},
+		{"https://Example.COM/page", "https://example.com/page", true},
+		{"https://example.com/page/", "https://example.com/page", true},
+		{"https://example.com/page#section", "https://example.com/page", true},
+		{"https://example.com:443/page", "https://example.com/page", true},
+		{"http://example.com:80/page", "https://example.com/page", true},
+		{"https://example.com/page?utm_source=x&id=1", "https://example.com/page?id=1", true},
+		{"https://example.com/page?b=2&a=1", "https://example.com/page?a=1&b=2", true},
+		{"  https://example.com/page ", "https://example.com/page", true},
+		{"https://example.com:8080/page", "https://example.com/page", false},
+		{"https://example.com/page?id=1", "https://example.com/page?id=2", false},
+		{"https://example.com/Page", "https://example.com/page", false},
+		{"https://sub.example.com/page", "https://example.com/page", false},
+	}
+	for _, tt := range tests {
+		a, b := normalizeURL(tt.a), normalizeURL(tt.b)
+		if (a == b) != tt.same {
+			t.Errorf("normalizeURL(%q) = %q, normalizeURL(%q) = %q, want same = %v", tt.a, a, tt.b, b, tt.same)
+		}
+	}
+}
+
+func urlsOf(list []SearchResultItem) []string {
+	urls := make([]string, 0, len(list))
+	for _, item := range list {
+		urls = append(urls, item.URL)
+	}
+	return urls
+}
+
+func TestFuseRankings(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []*SearchResult
+		limit   int
+		want    []string
+	}{
+		{
+			name: "results found by several providers rank first",
+			results: []*SearchResult{
+				{Type: SearchTypeBocha, Items: items("https://a.com/1", "https://a.com/2", "https://shared.com/x")},
+				{Type: SearchTypeTavily, Items: items("https://b.com/1", "https://shared.com/x")},
+			},
+			want: []string{"https://shared.com/x", "https://a.com/1", "https://b.com/1", "https://a.com/2"},
+		},
+		{
+			name: "duplicates are merged by normalized URL keeping the best ranked item",
+			results: []*SearchResult{
+				{Type: SearchTypeBocha, Items: items("https://a.com/1", "https://www.shared.com/x/")},
+				{Type: SearchTypeTavily, Items: items("http://shared.com/x?utm_source=t")},
+			},
+			want: []string{"http://shared.com/x?utm_source=t", "https://a.com/1"},
+		},
+		{
+			name: "ties keep provider chain order",
+			results: []*SearchResult{
+				{Type: SearchTypeBocha, Items: items("https://a.com/1", "https://a.com/2")},
+				{Type: SearchTypeTavily, Items: items("https://b.com/1", "https://b.com/2")},
+			},
+			want: []string{"https://a.com/1", "https://b.com/1", "https://a.com/2", "https://b.com/2"},
+		},
+		{
+			name: "duplicate within one provider counts once",
+			results: []*SearchResult{
+				{Type: SearchTypeBocha, Items: items("https://a.com/1", "https://a.com/1", "https://a.com/2")},
+				{Type: SearchTypeTavily, Items: items("https://b.com/1")},
+			},
+			want: []string{"https://a.com/1", "https://b.com/1", "https://a.com/2"},
+		},
+		{
+			name: "items without URL are kept separately",
+			results: []*SearchResult{
+				{Type: SearchTypeBocha, Items: []SearchResultItem{{Title: "no url"}}},
+				{Type: SearchTypeTavily, Items: []SearchResultItem{{Title: "no url either"}}},
+			},
+			want: []string{"", ""},
+		},
+		{
+			name: "limit truncates after ranking",
+			results: []*SearchResult{
+				{Type: SearchTypeBocha, Items: items("https://a.com/1", "https://shared.com/x")},
+				{Type: SearchTypeTavily, Items: items("https://b.com/1", "https://shared.com/x")},
+			},
+			limit: 2,
+			want:  []string{"https://shared.com/x", "https://a.com/1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := urlsOf(fuseRankings(tt.results, tt.limit))
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("fuseRankings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuseRankingsProvidersAndAnswer(t *testing.T) {
+	results := []*SearchResult{
+		{Type: SearchTypeBocha, Items: []SearchResultItem{
+			{URL: "https://a.com/1", Answer: "bocha answer"},
+			{URL: "https://shared.com/x"},
+		}},
+		{Type: SearchTypeTavily, Items: []SearchResultItem{
+			{URL: "https://shared.com/x", Answer: "tavily answer"},
+		}},
+	}
+	got := fuseRankings(results, 0)
+	if len(got) != 2 {
+		t.Fatalf("got %d items, want 2", len(got))
+	}
+	if got[0].URL != "https://shared.com/x" || !slices.Equal(got[0].Providers, []SearchType{SearchTypeBocha, SearchTypeTavily}) {
+		t.Fatalf("first item = %+v, want shared.com from bocha and tavily", got[0])
+	}
+	// 只保留第一个提供者的 AI 回答，放在第一条结果上
+	if got[0].Answer != "bocha answer" || got[1].Answer != "" {
+		t.Fatalf("answers = %q, %q; want %q on the first item only", got[0].Answer, got[1].Answer, "bocha answer")
+	}
+}
+
+func TestSearchFederated(t *testing.T) {
+	slow := &fakeProvider{items: items("https://slow.com/1"), delay: time.Minute}
+	s := newTestService(
+		namedProvider{SearchTypeBocha, &fakeProvider{items: items("https://a.com/1", "https://shared.com/x")}},
+		namedProvider{SearchTypeTavily, slow},
+		namedProvider{SearchTypeSerper, &fakeProvider{items: items("https://shared.com/x")}},
+		namedProvider{SearchTypeGoogle, &fakeProvider{err: errors.New("boom")}},
+	)
+
+	// 超时与出错的提供者不参与合并
+	res, err := s.SearchFederated(context.Background(), "q", &SearchOptions{MaxResults: 5}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("SearchFederated: %v", err)
+	}
+	if res.Type != SearchTypeFederated {
+		t.Fatalf("type = %s, want %s", res.Type, SearchTypeFederated)
+	}
+	if want := []SearchType{SearchTypeBocha, SearchTypeSerper}; !slices.Equal(res.Providers, want) {
+		t.Fatalf("providers = %v, want %v", res.Providers, want)
+	}
+	if want := []string{"https://shared.com/x", "https://a.com/1"}; !slices.Equal(urlsOf(res.Items), want) {
+		t.Fatalf("items = %v, want %v", urlsOf(res.Items), want)
+	}
+}
+
+func TestSearchFederatedAllFail(t *testing.T) {
+	s := newTestService(
+		namedProvider{SearchTypeBocha, &fakeProvider{err: &StatusError{StatusCode: 429}}},
+		namedProvider{SearchTypeTavily, &fakeProvider{err: errors.New("boom")}},
+	)
+	_, err := s.SearchFederated(context.Background(), "q", nil, time.Second)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("err = %v, want it to wrap ErrQuotaExceeded", err)
+	}
+}
@@ -60,7 +60,7 @@ func (p *BochaProvider) Search(ctx context.Context, query string, options *Searc
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search API returned error status: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var searchResp BochaSearchResponse
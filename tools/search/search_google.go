@@ -59,7 +59,7 @@ func (p *GoogleProvider) Search(ctx context.Context, query string, options *Sear
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search API returned error status: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var searchResp GoogleSearchResponse
@@ -2,9 +2,14 @@ package search
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"mcp/pkg/log"
+	"net/http"
 	"time"
+
+	"mcp/config"
+	"mcp/internal/breaker"
+	"mcp/pkg/log"
 )
 
 // SearchType 搜索类型
@@ -46,6 +51,8 @@ type SearchResultItem struct {
 	Content string   `json:"content"`
 	Images  []string `json:"images,omitempty"` // 图片链接列表
 	Answer  string   `json:"answer,omitempty"` // AI回答
+	// Providers 联合搜索时返回该结果的提供者
+	Providers []SearchType `json:"providers,omitempty"`
 }
 
 // SearchResult 搜索结果
@@ -53,58 +60,88 @@ type SearchResult struct {
 	Type  SearchType         `json:"type"`
 	Query string             `json:"query"`
 	Items []SearchResultItem `json:"items"`
+	// Providers 联合搜索时参与合并的提供者
+	Providers []SearchType `json:"providers,omitempty"`
+}
+
+// ProviderConfig 单个搜索提供者的配置
+type ProviderConfig struct {
+	Type   SearchType
+	APIKey string
+	CX     string // 仅用于 Google
 }
 
 // Config 搜索配置
 type Config struct {
-	GoogleAPIKey string
-	GoogleCX     string
-	TavilyAPIKey string
-	BochaAPIKey  string
-	SerperAPIKey string
+	// Providers 按顺序组成提供者链
+	Providers []ProviderConfig
+	// CircuitBreaker 每个提供者各自的熔断器
+	CircuitBreaker config.CircuitBreakerConfig
+}
+
+// ErrQuotaExceeded 表示提供者的调用额度或账户余额已用尽，可用 errors.Is 判断
+var ErrQuotaExceeded = errors.New("search quota exceeded")
+
+// StatusError 表示搜索 API 返回了非 200 的状态码
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("search API returned error status: %d", e.StatusCode)
 }
 
-// Service 搜索服务
+// Is 将额度相关的状态码识别为 ErrQuotaExceeded：
+// 429 请求过多，402 余额不足（Bocha），432/433 超出套餐或按量付费额度（Tavily）
+func (e *StatusError) Is(target error) bool {
+	if target != ErrQuotaExceeded {
+		return false
+	}
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusPaymentRequired, 432, 433:
+		return true
+	}
+	return false
+}
+
+// Service 搜索服务，按配置顺序持有多个提供者
 type Service struct {
 	providers map[SearchType]Provider
+	chain     []SearchType
 }
 
-// NewService 创建搜索服务
+// NewService 创建搜索服务，每个提供者都由各自的熔断器包装
 func NewService(cfg *Config) *Service {
 	s := &Service{
 		providers: make(map[SearchType]Provider),
 	}
 
-	// 根据配置初始化可用的 Provider
-	if cfg.GoogleAPIKey != "" && cfg.GoogleCX != "" {
-		s.providers[SearchTypeGoogle] = &GoogleProvider{
-			APIKey: cfg.GoogleAPIKey,
-			CX:     cfg.GoogleCX,
+	for _, pc := range cfg.Providers {
+		var provider Provider
+		switch pc.Type {
+		case SearchTypeGoogle:
+			provider = &GoogleProvider{APIKey: pc.APIKey, CX: pc.CX}
+		case SearchTypeTavily:
+			provider = &TavilyProvider{APIKey: pc.APIKey}
+		case SearchTypeBocha:
+			provider = &BochaProvider{APIKey: pc.APIKey}
+		case SearchTypeSerper:
+			provider = &SerperProvider{APIKey: pc.APIKey}
+		default:
+			log.Warn("忽略未知的搜索提供者", "provider", pc.Type)
+			continue
 		}
-	}
-
-	if cfg.TavilyAPIKey != "" {
-		s.providers[SearchTypeTavily] = &TavilyProvider{
-			APIKey: cfg.TavilyAPIKey,
-		}
-	}
-
-	if cfg.BochaAPIKey != "" {
-		s.providers[SearchTypeBocha] = &BochaProvider{
-			APIKey: cfg.BochaAPIKey,
-		}
-	}
-
-	if cfg.SerperAPIKey != "" {
-		s.providers[SearchTypeSerper] = &SerperProvider{
-			APIKey: cfg.SerperAPIKey,
+		if _, exists := s.providers[pc.Type]; exists {
+			continue
 		}
+		s.providers[pc.Type] = WithBreaker(provider, breaker.New("search/"+string(pc.Type), cfg.CircuitBreaker))
+		s.chain = append(s.chain, pc.Type)
 	}
 
 	return s
 }
 
-// Search 执行搜索
+// Search 使用指定的提供者执行搜索
 func (s *Service) Search(ctx context.Context, searchType SearchType, query string, options *SearchOptions) (*SearchResult, error) {
 	provider, ok := s.providers[searchType]
 	if !ok {
@@ -135,35 +172,42 @@ func (s *Service) Search(ctx context.Context, searchType SearchType, query strin
 	}, nil
 }
 
-// GetAvailableProviders 获取可用的搜索提供者
-func (s *Service) GetAvailableProviders() []SearchType {
-	types := make([]SearchType, 0, len(s.providers))
-	for t := range s.providers {
-		types = append(types, t)
+// SearchChain 按配置顺序依次尝试各提供者，返回第一个有结果的提供者的结果，结果的 Type 即作答的提供者
+// 提供者出错（包括熔断与额度用尽）或没有结果时尝试下一个；
+// 都没有结果时返回第一个正常作答的空结果，全部出错时返回各提供者的错误
+func (s *Service) SearchChain(ctx context.Context, query string, options *SearchOptions) (*SearchResult, error) {
+	if len(s.chain) == 0 {
+		return nil, fmt.Errorf("no search provider configured")
 	}
-	return types
-}
-
-// IsAvailable 检查搜索类型是否可用
-func (s *Service) IsAvailable(searchType SearchType) bool {
-	_, ok := s.providers[searchType]
-	return ok
-}
-
-// ValidSearchTypes 所有有效的搜索类型
-var ValidSearchTypes = []SearchType{
-	SearchTypeGoogle,
-	SearchTypeTavily,
-	SearchTypeBocha,
-	SearchTypeSerper,
-}
 
-// IsValidSearchType 检查是否为有效的搜索类型
-func IsValidSearchType(t string) bool {
-	for _, vt := range ValidSearchTypes {
-		if string(vt) == t {
-			return true
+	var errs []error
+	var empty *SearchResult
+	for _, t := range s.chain {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		res, err := s.Search(ctx, t, query, options)
+		if err != nil {
+			log.Warn("搜索提供者调用失败，尝试下一个", "provider", t, "quota_exceeded", errors.Is(err, ErrQuotaExceeded), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", t, err))
+			continue
+		}
+		if len(res.Items) > 0 {
+			return res, nil
+		}
+		log.Info("搜索提供者没有返回结果，尝试下一个", "provider", t)
+		if empty == nil {
+			empty = res
 		}
 	}
-	return false
+	if empty != nil {
+		return empty, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// GetAvailableProviders 按提供者链的顺序返回可用的搜索提供者
+func (s *Service) GetAvailableProviders() []SearchType {
+	return append([]SearchType(nil), s.chain...)
 }
@@ -0,0 +1,215 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"mcp/config"
+	"mcp/internal/breaker"
+)
+
+// fakeProvider 返回预设的结果或错误，并记录被调用的次数
+type fakeProvider struct {
+	items []SearchResultItem
+	err   error
+	// delay 返回前等待的时间，ctx 先结束时返回 ctx.Err()
+	delay time.Duration
+	calls int
+}
+
+func (p *fakeProvider) Search(ctx context.Context, query string, options *SearchOptions) ([]SearchResultItem, error) {
+	p.calls++
+	if p.delay > 0 {
+		select {
+		case <-time.After(p.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return p.items, p.err
+}
+
+type namedProvider struct {
+	name     SearchType
+	provider Provider
+}
+
+// newTestService 按给定顺序组成提供者链
+func newTestService(providers ...namedProvider) *Service {
+	s := &Service{providers: make(map[SearchType]Provider)}
+	for _, p := range providers {
+		s.providers[p.name] = p.provider
+		s.chain = append(s.chain, p.name)
+	}
+	return s
+}
+
+func items(urls ...string) []SearchResultItem {
+	list := make([]SearchResultItem, 0, len(urls))
+	for _, u := range urls {
+		list = append(list, SearchResultItem{Title: u, URL: u})
+	}
+	return list
+}
+
+func TestSearchChainFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		first     *fakeProvider
+		second    *fakeProvider
+		wantType  SearchType
+		wantItems int
+	}{
+		{
+			name:      "first provider answers",
+			first:     &fakeProvider{items: items("https://a.example/1")},
+			second:    &fakeProvider{items: items("https://b.example/1")},
+			wantType:  SearchTypeBocha,
+			wantItems: 1,
+		},
+		{
+			name:      "error falls back",
+			first:     &fakeProvider{err: errors.New("connection refused")},
+			second:    &fakeProvider{items: items("https://b.example/1", "https://b.example/2")},
+			wantType:  SearchTypeTavily,
+			wantItems: 2,
+		},
+		{
+			name:      "quota exceeded falls back",
+			first:     &fakeProvider{err: &StatusError{StatusCode: 432}},
+			second:    &fakeProvider{items: items("https://b.example/1")},
+			wantType:  SearchTypeTavily,
+			wantItems: 1,
+		},
+		{
+			name:      "open breaker falls back",
+			first:     &fakeProvider{err: &breaker.OpenError{Name: "search/bocha", RetryAfter: time.Second}},
+			second:    &fakeProvider{items: items("https://b.example/1")},
+			wantType:  SearchTypeTavily,
+			wantItems: 1,
+		},
+		{
+			name:      "empty result falls back",
+			first:     &fakeProvider{},
+			second:    &fakeProvider{items: items("https://b.example/1")},
+			wantType:  SearchTypeTavily,
+			wantItems: 1,
+		},
+		{
+			name:      "all empty returns first empty result",
+			first:     &fakeProvider{},
+			second:    &fakeProvider{},
+			wantType:  SearchTypeBocha,
+			wantItems: 0,
+		},
+		{
+			name:      "error then empty returns empty result",
+			first:     &fakeProvider{err: errors.New("boom")},
+			second:    &fakeProvider{},
+			wantType:  SearchTypeTavily,
+			wantItems: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(
+				namedProvider{SearchTypeBocha, tt.first},
+				namedProvider{SearchTypeTavily, tt.second},
+			)
+			res, err := s.SearchChain(context.Background(), "q", &SearchOptions{})
+			if err != nil {
+				t.Fatalf("SearchChain: %v", err)
+			}
+			if res.Type != tt.wantType || len(res.Items) != tt.wantItems {
+				t.Fatalf("got type %s with %d items, want %s with %d", res.Type, len(res.Items), tt.wantType, tt.wantItems)
+			}
+			if tt.wantType == SearchTypeBocha && len(res.Items) > 0 && tt.second.calls != 0 {
+				t.Fatalf("second provider called %d times after the first answered", tt.second.calls)
+			}
+		})
+	}
+}
+
+func TestSearchChainAllFail(t *testing.T) {
+	s := newTestService(
+		namedProvider{SearchTypeBocha, &fakeProvider{err: &StatusError{StatusCode: http.StatusPaymentRequired}}},
+		namedProvider{SearchTypeTavily, &fakeProvider{err: errors.New("boom")}},
+	)
+	_, err := s.SearchChain(context.Background(), "q", nil)
+	if err == nil {
+		t.Fatal("SearchChain succeeded, want error")
+	}
+	// 合并后的错误保留各提供者的错误
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("err = %v, want it to wrap ErrQuotaExceeded", err)
+	}
+}
+
+func TestSearchChainStopsWhenCallerCancels(t *testing.T) {
+	second := &fakeProvider{items: items("https://b.example/1")}
+	s := newTestService(
+		namedProvider{SearchTypeBocha, &fakeProvider{delay: time.Minute}},
+		namedProvider{SearchTypeTavily, second},
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.SearchChain(ctx, "q", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if second.calls != 0 {
+		t.Fatalf("second provider called %d times after the caller gave up", second.calls)
+	}
+}
+
+func TestStatusErrorQuota(t *testing.T) {
+	tests := []struct {
+		code  int
+		quota bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusPaymentRequired, true},
+		{432, true},
+		{433, true},
+		{http.StatusUnauthorized, false},
+		{http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		err := error(&StatusError{StatusCode: tt.code})
+		if got := errors.Is(err, ErrQuotaExceeded); got != tt.quota {
+			t.Errorf("status %d: errors.Is(ErrQuotaExceeded) = %v, want %v", tt.code, got, tt.quota)
+		}
+	}
+}
+
+func TestBreakerIgnoresCallerDeadline(t *testing.T) {
+	b := breaker.New("search/test", config.CircuitBreakerConfig{
+		Enabled:             true,
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Minute,
+		HalfOpenMaxCalls:    1,
+	})
+	slow := &fakeProvider{delay: time.Minute}
+	p := WithBreaker(slow, b)
+
+	// 到达调用方的截止时间不计为提供者失败
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.Search(ctx, "q", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if s := b.Snapshot(); s.State != "closed" || s.Failures != 0 {
+		t.Fatalf("after caller deadline: state %s, failures %d; want closed, 0", s.State, s.Failures)
+	}
+
+	// 提供者自身的错误照常计入
+	slow.delay, slow.err = 0, errors.New("boom")
+	if _, err := p.Search(context.Background(), "q", nil); err == nil {
+		t.Fatal("Search succeeded, want error")
+	}
+	if s := b.Snapshot(); s.State != "open" {
+		t.Fatalf("after provider error: state %s, want open", s.State)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp/internal/reqctx"
 )
 
 // TransportMap 存储了当前活跃的 SSE session
@@ -18,15 +20,20 @@ var TransportMap = sync.Map{}
 type SSEServerTransport struct {
 	SendChan chan any // 保持为 any 类型以提供 json.Marshal 灵活性, 但 Write 接收 jsonrpc.Message
 	recvChan chan jsonrpc.Message
+	done     chan struct{}
 	id       string
 	closed   bool
 	mutex    sync.Mutex
+
+	// Info 是建立 SSE 连接的请求信息，会话绑定到其中的调用方
+	Info *reqctx.Info
 }
 
 func NewSSEServerTransport() *SSEServerTransport {
 	t := &SSEServerTransport{
 		SendChan: make(chan any, 10),
 		recvChan: make(chan jsonrpc.Message, 10),
+		done:     make(chan struct{}),
 		id:       uuid.New().String(),
 	}
 	TransportMap.Store(t.id, t)
@@ -41,11 +48,10 @@ func (t *SSEServerTransport) Connect(ctx context.Context) (mcp.Connection, error
 // Read 实现 mcp.Connection 接口
 func (t *SSEServerTransport) Read(ctx context.Context) (jsonrpc.Message, error) {
 	select {
-	case msg, ok := <-t.recvChan:
-		if !ok {
-			return nil, io.EOF
-		}
+	case msg := <-t.recvChan:
 		return msg, nil
+	case <-t.done:
+		return nil, io.EOF
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
@@ -53,50 +59,49 @@ func (t *SSEServerTransport) Read(ctx context.Context) (jsonrpc.Message, error)
 
 // Write 实现 mcp.Connection 接口
 func (t *SSEServerTransport) Write(ctx context.Context, message jsonrpc.Message) error {
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
-	if t.closed {
-		return fmt.Errorf("transport closed")
-	}
+	// 不持有锁进行阻塞发送，否则客户端断开后 Close 会与 Write 互相等待
 	select {
 	case t.SendChan <- message:
 		return nil
+	case <-t.done:
+		return fmt.Errorf("transport closed")
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
 
 // Close 实现 mcp.Connection 接口
+// SendChan 与 recvChan 不会被关闭，读写双方通过 done 感知连接结束，避免向已关闭的 channel 发送
 func (t *SSEServerTransport) Close() error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 	if !t.closed {
 		t.closed = true
-		close(t.SendChan)
-		close(t.recvChan)
+		close(t.done)
 		TransportMap.Delete(t.id)
 	}
 	return nil
 }
 
+// Done 返回一个在 transport 关闭后被关闭的 channel
+func (t *SSEServerTransport) Done() <-chan struct{} {
+	return t.done
+}
+
 // SessionID 实现 mcp.Connection 接口
 func (t *SSEServerTransport) SessionID() string {
 	return t.id
 }
 
 // HandleMessage 将被 POST 路由调用来注入客户端发来的消息
-func (t *SSEServerTransport) HandleMessage(msg jsonrpc.Message) {
-	t.mutex.Lock()
-	if t.closed {
-		t.mutex.Unlock()
-		return
-	}
-	t.mutex.Unlock()
-
+// 当 session 已结束时返回错误，调用方据此向客户端报告
+func (t *SSEServerTransport) HandleMessage(ctx context.Context, msg jsonrpc.Message) error {
 	select {
 	case t.recvChan <- msg:
-	default:
-		// 阻塞式的后备写入策略
-		t.recvChan <- msg
+		return nil
+	case <-t.done:
+		return fmt.Errorf("transport closed")
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }